@@ -0,0 +1,134 @@
+package frontmatter_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+)
+
+type benchFormat struct {
+	name  string
+	start string
+	end   string
+	pair  func(key string, i int) string
+	open  string
+	close string
+}
+
+var benchFormats = []benchFormat{
+	{name: "yaml", start: "---", end: "---", pair: yamlPair},
+	{name: "yaml-tagged", start: "---yaml", end: "---", pair: yamlPair},
+	{name: "toml", start: "+++", end: "+++", pair: tomlPair},
+	{name: "toml-tagged", start: "---toml", end: "---", pair: tomlPair},
+	{name: "json", start: ";;;", end: ";;;", pair: jsonPair, open: "{", close: "}"},
+	{name: "json-tagged", start: "---json", end: "---", pair: jsonPair, open: "{", close: "}"},
+	{name: "json-bare", pair: jsonPair, open: "{", close: "}\n"},
+}
+
+var benchSizes = []struct {
+	name string
+	keys int
+	body int
+}{
+	{name: "small", keys: 5, body: 1 << 7},
+	{name: "medium", keys: 50, body: 1 << 14},
+	{name: "large", keys: 500, body: 1 << 20},
+}
+
+func yamlPair(key string, i int) string {
+	return fmt.Sprintf("%s: %d\n", key, i)
+}
+
+func tomlPair(key string, i int) string {
+	return fmt.Sprintf("%s = %d\n", key, i)
+}
+
+func jsonPair(key string, i int) string {
+	return fmt.Sprintf("%q: %d", key, i)
+}
+
+func benchInput(f benchFormat, keys, body int) string {
+	var sb strings.Builder
+	if f.start != "" {
+		sb.WriteString(f.start + "\n")
+	}
+	if f.open != "" {
+		sb.WriteString(f.open + "\n")
+	}
+	for i := 0; i < keys; i++ {
+		sb.WriteString(f.pair(fmt.Sprintf("key%d", i), i))
+		if f.open != "" {
+			if i < keys-1 {
+				sb.WriteByte(',')
+			}
+			sb.WriteByte('\n')
+		}
+	}
+	if f.close != "" {
+		sb.WriteString(f.close + "\n")
+	}
+	if f.end != "" {
+		sb.WriteString(f.end + "\n")
+	}
+
+	line := "Lorem ipsum dolor sit amet, consectetur adipiscing elit.\n"
+	sb.WriteString(strings.Repeat(line, body/len(line)+1)[:body])
+	return sb.String()
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, size := range benchSizes {
+		for _, f := range benchFormats {
+			input := benchInput(f, size.keys, size.body)
+
+			b.Run(size.name+"/"+f.name, func(b *testing.B) {
+				b.ReportAllocs()
+				b.SetBytes(int64(len(input)))
+
+				for i := 0; i < b.N; i++ {
+					var m map[string]interface{}
+					if _, err := frontmatter.MustParse(strings.NewReader(input), &m); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkParseNoMatter(b *testing.B) {
+	for _, size := range benchSizes {
+		input := benchInput(benchFormat{}, 0, size.body)
+
+		b.Run(size.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+
+			for i := 0; i < b.N; i++ {
+				if _, err := frontmatter.Parse(strings.NewReader(input), nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	for _, size := range benchSizes {
+		input := []byte(benchInput(benchFormats[0], size.keys, size.body))
+
+		b.Run(size.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(input)))
+
+			for i := 0; i < b.N; i++ {
+				var m map[string]interface{}
+				if _, err := frontmatter.MustParseBytes(input, &m); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package frontmatter
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// cacheHeader identifies the cache files, and contains the version of the
+// cache file format. Cache files having a different header are ignored.
+const cacheHeader = "frontmatter-cache 2\n"
+
+// maxCacheRecord is the maximum size of an encoded cache record.
+const maxCacheRecord = 1 << 30
+
+func init() {
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+	gob.Register(time.Time{})
+	gob.Register(cacheTime{})
+}
+
+// Changes reports the parts of a document which changed.
+type Changes uint8
+
+// Document changes.
+const (
+	// MatterChanged reports that the front matter changed.
+	MatterChanged Changes = 1 << iota
+
+	// BodyChanged reports that the body changed.
+	BodyChanged
+)
+
+// CacheEntry contains the cached information of a document.
+type CacheEntry struct {
+	// ModTime is the modification time of the document.
+	ModTime time.Time
+
+	// Size is the size of the document, in bytes.
+	Size int64
+
+	// Hash is the SHA-256 digest of the document content.
+	Hash string
+
+	// Format is the start delimiter of the front matter format of the
+	// document (e.g. `---` or `+++`), or its line prefix for header front
+	// matters. It is empty if the document does not have front matter.
+	Format string
+
+	// Matter is the decoded front matter of the document.
+	// It must not be modified.
+	Matter Matter
+
+	// MatterDigest is the canonical digest of the front matter,
+	// as returned by `Digest`.
+	MatterDigest string
+
+	// BodyDigest is the digest of the document body.
+	BodyDigest string
+}
+
+// Cache stores the decoded front matter of documents in a file, in order to
+// skip decoding the documents which did not change. Documents are decoded
+// again only if their modification time or size changed, and the digest of
+// their content is different from the cached one. Cache methods are safe
+// for concurrent use.
+//
+// The cache file is an append-only log of document records: saving the
+// cache only appends the records of the documents which were loaded or
+// removed since the last save. The file is rewritten when it contains more
+// stale records than current ones. Records which cannot be decoded (e.g.
+// the last record of an interrupted save) are discarded, along with the
+// records which follow them.
+type Cache struct {
+	path    string
+	fsys    fs.FS
+	opts    *Options
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+	pending map[string]*CacheEntry
+	size    int64
+	records int
+}
+
+// cacheRecord is a record of the cache file. A nil entry marks the removal
+// of the document.
+type cacheRecord struct {
+	Name  string
+	Entry *CacheEntry
+}
+
+// OpenCache returns a cache stored in the specified file, containing the
+// documents of the specified file system. The documents are parsed using
+// the provided options, if any. If the cache file does not exist, or if
+// it cannot be decoded, the cache starts empty.
+func OpenCache(path string, fsys fs.FS, opts *Options) (*Cache, error) {
+	c := &Cache{
+		path:    path,
+		fsys:    fsys,
+		opts:    opts,
+		entries: map[string]*CacheEntry{},
+		pending: map[string]*CacheEntry{},
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header := make([]byte, len(cacheHeader))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != cacheHeader {
+		return c, nil
+	}
+	c.size = int64(len(header))
+
+	for {
+		rec, n, err := readCacheRecord(r)
+		if err != nil {
+			break
+		}
+		c.size += n
+		c.records++
+
+		if rec.Entry == nil {
+			delete(c.entries, rec.Name)
+			continue
+		}
+		c.entries[rec.Name] = rec.Entry
+	}
+
+	return c, nil
+}
+
+// Names returns the sorted names of the cached documents.
+func (c *Cache) Names() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make([]string, 0, len(c.entries))
+	for name := range c.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Entry returns the cached information of the specified document, without
+// checking whether the document changed. It returns nil if the document is
+// not cached. The entry must not be modified.
+func (c *Cache) Entry(name string) *CacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries[name]
+}
+
+// Load returns the cached information of the specified document, decoding
+// the document only if it changed since it was last loaded. It also reports
+// which parts of the document changed. New documents are reported as having
+// both their front matter and body changed.
+func (c *Cache) Load(name string) (*CacheEntry, Changes, error) {
+	info, err := fs.Stat(c.fsys, name)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	c.mu.Lock()
+	prev := c.entries[name]
+	c.mu.Unlock()
+
+	// Skip reading the documents whose modification time and size did
+	// not change.
+	if prev != nil && prev.ModTime.Equal(info.ModTime()) && prev.Size == info.Size() {
+		return prev, 0, nil
+	}
+
+	data, err := fs.ReadFile(c.fsys, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	entry := &CacheEntry{ModTime: info.ModTime(), Size: info.Size(), Hash: hash}
+	if prev != nil && prev.Hash == hash {
+		// The content did not change, so the decoding is skipped.
+		entry.Format = prev.Format
+		entry.Matter = prev.Matter
+		entry.MatterDigest = prev.MatterDigest
+		entry.BodyDigest = prev.BodyDigest
+	} else {
+		var v interface{}
+		doc, err := ParseDocument(bytes.NewReader(data), &v, c.opts)
+		if err != nil {
+			return nil, 0, &fs.PathError{Op: "parse", Path: name, Err: err}
+		}
+
+		m, err := newMatter(v)
+		if err != nil {
+			return nil, 0, &fs.PathError{Op: "parse", Path: name, Err: err}
+		}
+		if entry.MatterDigest, err = Digest(m); err != nil {
+			return nil, 0, &fs.PathError{Op: "parse", Path: name, Err: err}
+		}
+		if f := doc.Format; f != nil {
+			if entry.Format = f.Start; entry.Format == "" {
+				entry.Format = f.Prefix
+			}
+		}
+		entry.Matter = m
+		entry.BodyDigest = doc.BodyDigest()
+	}
+
+	var changes Changes
+	if prev == nil || prev.MatterDigest != entry.MatterDigest {
+		changes |= MatterChanged
+	}
+	if prev == nil || prev.BodyDigest != entry.BodyDigest {
+		changes |= BodyChanged
+	}
+
+	c.mu.Lock()
+	c.entries[name] = entry
+	c.pending[name] = entry
+	c.mu.Unlock()
+
+	return entry, changes, nil
+}
+
+// Remove removes the specified document from the cache.
+func (c *Cache) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[name]; ok {
+		delete(c.entries, name)
+		c.pending[name] = nil
+	}
+}
+
+// Save writes the changes of the cache to its file, if any. The records of
+// the changed documents are appended to the file, unless the file has to
+// be rewritten, in which case it is replaced atomically.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(c.pending))
+	for name := range c.pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Rewrite the file if it does not exist, or if most of its records
+	// would be stale after appending the pending ones.
+	if c.size == 0 || c.records+len(names)-len(c.entries) > len(c.entries) {
+		return c.rewrite()
+	}
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		if err := writeCacheRecord(&buf, cacheRecord{Name: name, Entry: c.pending[name]}); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(c.path, os.O_WRONLY, 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c.rewrite()
+	}
+	if err != nil {
+		return err
+	}
+
+	// Discard the records which could not be decoded, if any.
+	if err := f.Truncate(c.size); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.WriteAt(buf.Bytes(), c.size); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	c.size += int64(buf.Len())
+	c.records += len(names)
+	c.pending = map[string]*CacheEntry{}
+	return nil
+}
+
+// rewrite replaces the cache file with a file containing only the records
+// of the cached documents. The caller must hold the lock.
+func (c *Cache) rewrite() error {
+	names := make([]string, 0, len(c.entries))
+	for name := range c.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBufferString(cacheHeader)
+	for _, name := range names {
+		if err := writeCacheRecord(buf, cacheRecord{Name: name, Entry: c.entries[name]}); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return err
+	}
+
+	c.size = int64(buf.Len())
+	c.records = len(names)
+	c.pending = map[string]*CacheEntry{}
+	return nil
+}
+
+// cacheTime represents a time having a named fixed zone (e.g. TOML local
+// dates) in the cache file, as the gob encoding of times does not preserve
+// the names of the zones.
+type cacheTime struct {
+	Time   time.Time
+	Zone   string
+	Offset int
+}
+
+// encodeTimes returns a copy of the specified value, in which the times
+// having a named fixed zone are replaced by cache times.
+func encodeTimes(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[key] = encodeTimes(val)
+		}
+		return m
+	case Matter:
+		return Matter(encodeTimes(map[string]interface{}(t)).(map[string]interface{}))
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = encodeTimes(val)
+		}
+		return s
+	case time.Time:
+		if name, offset := t.Zone(); name != "" && t.Location() != time.UTC && t.Location() != time.Local {
+			return cacheTime{Time: t, Zone: name, Offset: offset}
+		}
+	}
+
+	return v
+}
+
+// decodeTimes replaces the cache times contained by the specified value
+// with the times they represent, in place.
+func decodeTimes(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, val := range t {
+			t[key] = decodeTimes(val)
+		}
+	case Matter:
+		decodeTimes(map[string]interface{}(t))
+	case []interface{}:
+		for i, val := range t {
+			t[i] = decodeTimes(val)
+		}
+	case cacheTime:
+		return t.Time.In(time.FixedZone(t.Zone, t.Offset))
+	}
+
+	return v
+}
+
+// writeCacheRecord writes the specified record to w, prefixed by its length
+// and its CRC-32 checksum.
+func writeCacheRecord(w io.Writer, rec cacheRecord) error {
+	if rec.Entry != nil {
+		entry := *rec.Entry
+		entry.Matter = encodeTimes(entry.Matter).(Matter)
+		rec.Entry = &entry
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&rec); err != nil {
+		return err
+	}
+
+	var prefix [8]byte
+	binary.BigEndian.PutUint32(prefix[:4], uint32(buf.Len()))
+	binary.BigEndian.PutUint32(prefix[4:], crc32.ChecksumIEEE(buf.Bytes()))
+	if _, err := w.Write(prefix[:]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// readCacheRecord reads a record written by writeCacheRecord from r.
+// It returns the record, along with the number of bytes read.
+func readCacheRecord(r io.Reader) (cacheRecord, int64, error) {
+	var rec cacheRecord
+
+	var prefix [8]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return rec, 0, err
+	}
+
+	size := binary.BigEndian.Uint32(prefix[:4])
+	if size > maxCacheRecord {
+		return rec, 0, errors.New("invalid cache record size")
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return rec, 0, err
+	}
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(prefix[4:]) {
+		return rec, 0, errors.New("invalid cache record checksum")
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&rec); err != nil || rec.Name == "" {
+		return rec, 0, errors.New("invalid cache record")
+	}
+	if rec.Entry != nil {
+		decodeTimes(rec.Entry.Matter)
+	}
+
+	return rec, int64(len(prefix)) + int64(size), nil
+}
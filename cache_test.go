@@ -0,0 +1,209 @@
+package frontmatter_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestCache(t *testing.T) {
+	dir := t.TempDir()
+	docs := filepath.Join(dir, "docs")
+	if err := os.Mkdir(docs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(docs, "post.md")
+	mtime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	write := func(data string) {
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		mtime = mtime.Add(time.Second)
+		if err := os.Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cachePath := filepath.Join(dir, "cache.gob")
+	load := func(exp frontmatter.Changes) *frontmatter.CacheEntry {
+		t.Helper()
+
+		c, err := frontmatter.OpenCache(cachePath, os.DirFS(docs), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entry, changes, err := c.Load("post.md")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if changes != exp {
+			t.Fatalf("expected changes %b, got %b", exp, changes)
+		}
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		return entry
+	}
+
+	write("---\ntitle: Post\ntags: [go]\n---\nbody")
+	entry := load(frontmatter.MatterChanged | frontmatter.BodyChanged)
+	if entry.Matter.String("title") != "Post" || entry.Matter.String("tags.0") != "go" {
+		t.Fatalf("unexpected front matter: %v", entry.Matter)
+	}
+
+	// Unchanged documents.
+	load(0)
+	write("---\ntitle: Post\ntags: [go]\n---\nbody")
+	load(0)
+
+	// Key order and format changes do not affect the front matter digest.
+	write("+++\ntags = [\"go\"]\ntitle = \"Post\"\n+++\nbody")
+	load(0)
+
+	write("+++\ntags = [\"go\"]\ntitle = \"Post\"\n+++\nnew body")
+	load(frontmatter.BodyChanged)
+
+	write("---\ntitle: New post\ntags: [go]\n---\nnew body")
+	if entry = load(frontmatter.MatterChanged); entry.Matter.String("title") != "New post" {
+		t.Fatalf("unexpected front matter: %v", entry.Matter)
+	}
+
+	// Invalid cache files are ignored.
+	if err := os.WriteFile(cachePath, []byte("invalid"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	load(frontmatter.MatterChanged | frontmatter.BodyChanged)
+
+	// Removed documents.
+	c, err := frontmatter.OpenCache(cachePath, os.DirFS(docs), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Remove("post.md")
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	load(frontmatter.MatterChanged | frontmatter.BodyChanged)
+
+	if _, _, err := c.Load("missing.md"); err == nil {
+		t.Fatal("expected error for missing document")
+	}
+}
+
+func TestCacheLog(t *testing.T) {
+	dir := t.TempDir()
+	docs := filepath.Join(dir, "docs")
+	if err := os.Mkdir(docs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.md", "b.md", "c.md", "d.md", "e.md", "f.md"} {
+		data := "---\ntitle: " + name + "\n---\n"
+		if err := os.WriteFile(filepath.Join(docs, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cachePath := filepath.Join(dir, "cache")
+	open := func() *frontmatter.Cache {
+		t.Helper()
+
+		c, err := frontmatter.OpenCache(cachePath, os.DirFS(docs), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	save := func(c *frontmatter.Cache) []byte {
+		t.Helper()
+
+		if err := c.Save(); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(cachePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	c := open()
+	for _, name := range []string{"f.md", "c.md", "a.md", "b.md", "e.md", "d.md"} {
+		if _, _, err := c.Load(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := save(c)
+
+	c = open()
+	if names := c.Names(); !reflect.DeepEqual(names, []string{"a.md", "b.md", "c.md", "d.md", "e.md", "f.md"}) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+	if entry := c.Entry("b.md"); entry == nil || entry.Format != "---" || entry.Matter.String("title") != "b.md" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if c.Entry("missing.md") != nil {
+		t.Fatal("expected no entry for missing document")
+	}
+
+	// Saving only appends the records of the changed documents.
+	c.Remove("f.md")
+	appended := save(c)
+	if len(appended) <= len(data) || !bytes.Equal(appended[:len(data)], data) {
+		t.Fatal("expected the cache file to be appended to")
+	}
+	if save(c); c.Entry("f.md") != nil {
+		t.Fatal("expected removed document not to be cached")
+	}
+
+	// Records which cannot be decoded are discarded.
+	corrupt := append(append([]byte(nil), appended...), 0, 0, 0, 10, 1, 2)
+	if err := os.WriteFile(cachePath, corrupt, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c = open()
+	if names := c.Names(); !reflect.DeepEqual(names, []string{"a.md", "b.md", "c.md", "d.md", "e.md"}) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+	c.Remove("e.md")
+	if data := save(c); len(data) <= len(appended) || !bytes.Equal(data[:len(appended)], appended) {
+		t.Fatal("expected the invalid records to be discarded")
+	}
+	if names := open().Names(); !reflect.DeepEqual(names, []string{"a.md", "b.md", "c.md", "d.md"}) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+
+	// The file is rewritten once most of its records are stale.
+	c = open()
+	c.Remove("d.md")
+	if data := save(c); len(data) >= len(appended) {
+		t.Fatal("expected the cache file to be rewritten")
+	}
+	if names := open().Names(); !reflect.DeepEqual(names, []string{"a.md", "b.md", "c.md"}) {
+		t.Fatalf("unexpected names: %v", names)
+	}
+
+	// TOML local dates keep their location.
+	data = []byte("+++\ndate = 2024-05-06\n+++\n")
+	if err := os.WriteFile(filepath.Join(docs, "toml.md"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c = open()
+	if _, _, err := c.Load("toml.md"); err != nil {
+		t.Fatal(err)
+	}
+	save(c)
+
+	date, _ := open().Entry("toml.md").Matter.Get("date")
+	if d, ok := date.(time.Time); !ok || d.Location().String() != "date-local" || d.Format("2006-01-02") != "2024-05-06" {
+		t.Fatalf("unexpected date: %v", date)
+	}
+}
@@ -0,0 +1,211 @@
+package frontmatter
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sync"
+)
+
+// Cascade resolves the effective front matter of the documents contained
+// by a file system, by merging the defaults defined at directory level
+// with the values of each document. The defaults of a directory apply to
+// all the documents below it, and are merged over the defaults of the
+// parent directories. The defaults of each directory are read once and
+// cached, so changes to the files are not reflected.
+type Cascade struct {
+	// FS is the file system containing the documents.
+	FS fs.FS
+
+	// IndexFiles defines the names of the documents whose front matter
+	// provides the defaults for their directory.
+	// If no names are provided, `_index.md` is used.
+	IndexFiles []string
+
+	// DefaultsFiles defines the names of the data files which provide the
+	// defaults for their directory. The files are decoded entirely, using
+	// the unmarshaler registered for their extension.
+	// E.g.: `_defaults.yaml` or `_defaults.toml`.
+	DefaultsFiles []string
+
+	// Key defines the front matter key of the index files which contains
+	// the defaults (e.g. `cascade`). If empty, the whole front matter of
+	// the index files is used as defaults.
+	Key string
+
+	// Pattern defines the pattern matched against the names of the files
+	// visited by `Walk`, using the syntax of `path.Match`. Defaults files
+	// are never visited. If empty, `*.md` is used.
+	Pattern string
+
+	// Unmarshalers maps file extensions to the unmarshal functions used to
+	// decode the defaults files. If nil, the YAML (`.yaml` and `.yml`),
+	// TOML (`.toml`) and JSON (`.json`) unmarshalers are used.
+	Unmarshalers map[string]UnmarshalFunc
+
+	// Merge defines how the defaults and the values are merged.
+	Merge MergeOptions
+
+	// Options holds the configuration used to parse the documents.
+	// The `DecodeHooks` are not used, and the `Includes` are resolved
+	// relative to each document.
+	Options *Options
+
+	mu       sync.Mutex
+	base     map[string]Matter
+	defaults map[string]Matter
+}
+
+// Defaults returns the defaults of the specified directory.
+func (c *Cascade) Defaults(dir string) (Matter, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, err := c.dirDefaults(path.Clean(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	return Merge(m, nil, nil), nil
+}
+
+// Resolve parses the specified document and returns its effective front
+// matter, along with its body. Index files inherit the defaults of the
+// parent directories and the defaults files of their own directory.
+func (c *Cascade) Resolve(name string) (Matter, []byte, error) {
+	name = path.Clean(name)
+	m, body, err := parseFile(c.FS, c.Options, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var defaults Matter
+	if dir := path.Dir(name); c.isIndex(path.Base(name)) {
+		defaults, err = c.baseDefaults(dir)
+	} else {
+		defaults, err = c.dirDefaults(dir)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return Merge(defaults, m, &c.Merge), body, nil
+}
+
+// Walk resolves the documents found in the specified directory and its
+// subdirectories, in lexical order, and calls fn for each of them. If fn
+// returns an error, the walk is stopped and the error is returned.
+func (c *Cascade) Walk(root string, fn func(name string, m Matter, body []byte) error) error {
+	return Walk(c.FS, root, c.Pattern, func(name string, d fs.DirEntry) error {
+		if c.isDefaults(path.Base(name)) {
+			return nil
+		}
+
+		m, body, err := c.Resolve(name)
+		if err != nil {
+			return err
+		}
+		return fn(name, m, body)
+	})
+}
+
+// baseDefaults returns the defaults of the specified directory, excluding
+// the values of its index files.
+func (c *Cascade) baseDefaults(dir string) (Matter, error) {
+	if m, ok := c.base[dir]; ok {
+		return m, nil
+	}
+
+	m := Matter{}
+	if dir != "." && dir != "/" {
+		parent, err := c.dirDefaults(path.Dir(dir))
+		if err != nil {
+			return nil, err
+		}
+		m = parent
+	}
+
+	for _, name := range c.DefaultsFiles {
+		vals, err := readData(c.FS, c.Unmarshalers, path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if vals != nil {
+			m = Merge(m, vals, &c.Merge)
+		}
+	}
+
+	if c.base == nil {
+		c.base = map[string]Matter{}
+	}
+	c.base[dir] = m
+
+	return m, nil
+}
+
+// dirDefaults returns the defaults of the specified directory.
+func (c *Cascade) dirDefaults(dir string) (Matter, error) {
+	if m, ok := c.defaults[dir]; ok {
+		return m, nil
+	}
+
+	m, err := c.baseDefaults(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range c.indexFiles() {
+		vals, _, err := parseFile(c.FS, c.Options, path.Join(dir, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if c.Key != "" {
+			vals = vals.Map(c.Key)
+		}
+		if vals != nil {
+			m = Merge(m, vals, &c.Merge)
+		}
+	}
+
+	if c.defaults == nil {
+		c.defaults = map[string]Matter{}
+	}
+	c.defaults[dir] = m
+
+	return m, nil
+}
+
+func (c *Cascade) indexFiles() []string {
+	if len(c.IndexFiles) == 0 {
+		return []string{"_index.md"}
+	}
+
+	return c.IndexFiles
+}
+
+func (c *Cascade) isIndex(name string) bool {
+	for _, index := range c.indexFiles() {
+		if name == index {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Cascade) isDefaults(name string) bool {
+	for _, defaults := range c.DefaultsFiles {
+		if name == defaults {
+			return true
+		}
+	}
+
+	return false
+}
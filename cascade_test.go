@@ -0,0 +1,180 @@
+package frontmatter_test
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestMerge(t *testing.T) {
+	defaults := frontmatter.Matter{
+		"title":  "default",
+		"tags":   []interface{}{"go", "yaml"},
+		"author": map[string]interface{}{"name": "John Doe", "email": "john@example.com"},
+	}
+	values := frontmatter.Matter{
+		"title":  "post",
+		"tags":   []interface{}{"yaml", "toml"},
+		"author": map[interface{}]interface{}{"name": "Jane Doe"},
+	}
+
+	inputs := []struct {
+		opts *frontmatter.MergeOptions
+		exp  frontmatter.Matter
+	}{
+		{
+			nil,
+			frontmatter.Matter{
+				"title":  "post",
+				"tags":   []interface{}{"yaml", "toml"},
+				"author": map[string]interface{}{"name": "Jane Doe", "email": "john@example.com"},
+			},
+		},
+		{
+			&frontmatter.MergeOptions{Lists: frontmatter.ListAppend, Maps: frontmatter.MapReplace},
+			frontmatter.Matter{
+				"title":  "post",
+				"tags":   []interface{}{"go", "yaml", "yaml", "toml"},
+				"author": map[string]interface{}{"name": "Jane Doe"},
+			},
+		},
+		{
+			&frontmatter.MergeOptions{Lists: frontmatter.ListUnion},
+			frontmatter.Matter{
+				"title":  "post",
+				"tags":   []interface{}{"go", "yaml", "toml"},
+				"author": map[string]interface{}{"name": "Jane Doe", "email": "john@example.com"},
+			},
+		},
+	}
+
+	for i, input := range inputs {
+		if m := frontmatter.Merge(defaults, values, input.opts); !reflect.DeepEqual(m, input.exp) {
+			t.Fatalf("input %d: expected %v, got %v", i, input.exp, m)
+		}
+	}
+
+	// The merged front matters must not be modified.
+	if len(defaults["tags"].([]interface{})) != 2 || len(defaults["author"].(map[string]interface{})) != 2 {
+		t.Fatalf("defaults modified: %v", defaults)
+	}
+}
+
+func TestCascade(t *testing.T) {
+	fsys := fstest.MapFS{
+		"_defaults.yaml": {Data: []byte("layout: page\ntags: [site]\n")},
+		"_index.md": {Data: []byte(`---
+title: Home
+params:
+  color: red
+  size: 1
+---
+home`)},
+		"about.md":            {Data: []byte("---\ntitle: About\n---\nabout")},
+		"blog/_defaults.toml": {Data: []byte(`layout = "post"`)},
+		"blog/_index.md": {Data: []byte(`+++
+title = "Blog"
+[cascade]
+draft = false
+tags = ["blog"]
+[cascade.params]
+color = "blue"
++++
+blog`)},
+		"blog/first.md": {Data: []byte(`---
+title: First
+tags: [go]
+params:
+  size: 2
+---
+first`)},
+		"blog/image.png": {Data: []byte("PNG")},
+	}
+
+	c := &frontmatter.Cascade{
+		FS:            fsys,
+		DefaultsFiles: []string{"_defaults.yaml", "_defaults.toml"},
+		Key:           "cascade",
+		Merge:         frontmatter.MergeOptions{Lists: frontmatter.ListAppend},
+	}
+
+	exp := map[string]frontmatter.Matter{
+		"_index.md": {
+			"layout": "page",
+			"tags":   []interface{}{"site"},
+			"title":  "Home",
+			"params": map[string]interface{}{"color": "red", "size": 1},
+		},
+		"about.md": {
+			"layout": "page",
+			"tags":   []interface{}{"site"},
+			"title":  "About",
+		},
+		"blog/_index.md": {
+			"layout": "post",
+			"tags":   []interface{}{"site"},
+			"title":  "Blog",
+			"cascade": map[string]interface{}{
+				"draft":  false,
+				"tags":   []interface{}{"blog"},
+				"params": map[string]interface{}{"color": "blue"},
+			},
+		},
+		"blog/first.md": {
+			"layout": "post",
+			"tags":   []interface{}{"site", "blog", "go"},
+			"title":  "First",
+			"draft":  false,
+			"params": map[string]interface{}{"color": "blue", "size": 2},
+		},
+	}
+
+	// Only the index files of the root directory are used without a key.
+	c.Key = ""
+	m, _, err := c.Resolve("about.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.String("params.color") != "red" || m.String("title") != "About" {
+		t.Fatalf("unexpected front matter: %v", m)
+	}
+
+	c = &frontmatter.Cascade{
+		FS:            fsys,
+		DefaultsFiles: c.DefaultsFiles,
+		Key:           "cascade",
+		Merge:         c.Merge,
+	}
+
+	visited := map[string]bool{}
+	err = c.Walk(".", func(name string, m frontmatter.Matter, body []byte) error {
+		visited[name] = true
+		if !reflect.DeepEqual(m, exp[name]) {
+			t.Fatalf("%s: expected %v, got %v", name, exp[name], m)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visited) != len(exp) {
+		t.Fatalf("expected %d documents, got %v", len(exp), visited)
+	}
+
+	defaults, err := c.Defaults("blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if defaults.String("layout") != "post" || defaults.String("params.color") != "blue" {
+		t.Fatalf("unexpected defaults: %v", defaults)
+	}
+
+	// Invalid defaults files.
+	fsys["blog/_defaults.toml"] = &fstest.MapFile{Data: []byte("layout = ")}
+	c = &frontmatter.Cascade{FS: fsys, DefaultsFiles: []string{"_defaults.toml"}}
+	if _, _, err := c.Resolve("blog/first.md"); err == nil {
+		t.Fatal("expected error for invalid defaults file")
+	}
+}
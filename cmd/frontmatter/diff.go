@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/adrg/frontmatter"
+)
+
+type jsonChange struct {
+	Type string      `json:"type"`
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// runDiff reports the front matter changes between two documents. Besides
+// the paths of the documents, it accepts the 7 arguments passed by git to
+// external diff drivers: path old-file old-hex old-mode new-file new-hex
+// new-mode.
+func runDiff(args []string, stdout, stderr io.Writer) (int, error) {
+	fs := newFlagSet("diff", "<old> <new>", stderr)
+	var (
+		jsonOutput = fs.Bool("json", false, "output the changes as JSON")
+		exitCode   = fs.Bool("exit-code", false, "exit with status 1 if there are changes")
+		html       = fs.Bool("html", false, "detect front matters in HTML comments")
+		tail       = fs.Bool("tail", false, "search for front matters at the end of the documents")
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2, err
+	}
+
+	var oldName, newName, oldLabel, newLabel string
+	switch args = fs.Args(); len(args) {
+	case 2:
+		oldName, newName = args[0], args[1]
+		oldLabel, newLabel = oldName, newName
+	case 7:
+		oldName, newName = args[1], args[4]
+		oldLabel, newLabel = "a/"+args[0], "b/"+args[0]
+	default:
+		fs.Usage()
+		return 2, fmt.Errorf("expected 2 or 7 arguments, got %d", len(args))
+	}
+
+	opts := &frontmatter.Options{HTMLComments: *html, Tail: *tail}
+	oldData, err := readDocument(oldName)
+	if err != nil {
+		return 2, err
+	}
+	newData, err := readDocument(newName)
+	if err != nil {
+		return 2, err
+	}
+
+	changes, err := frontmatter.Diff(bytes.NewReader(oldData), bytes.NewReader(newData), opts)
+	if err != nil {
+		return 2, err
+	}
+
+	if *jsonOutput {
+		out := make([]jsonChange, 0, len(changes))
+		for _, c := range changes {
+			out = append(out, jsonChange{Type: c.Type.String(), Path: c.Path, Old: c.Old, New: c.New})
+		}
+
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return 2, err
+		}
+	} else if len(changes) > 0 {
+		fmt.Fprintf(stdout, "--- %s\n+++ %s\n", oldLabel, newLabel)
+		for _, c := range changes {
+			fmt.Fprintln(stdout, c)
+		}
+	}
+
+	if *exitCode && len(changes) > 0 {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// readDocument returns the content of the specified document. The null
+// device, used by git for added and removed files, is read as empty.
+func readDocument(name string) ([]byte, error) {
+	if name == os.DevNull || name == "/dev/null" {
+		return nil, nil
+	}
+
+	return os.ReadFile(name)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/lint"
+)
+
+// runLint checks the style and consistency of the front matter of the
+// documents of a directory. It exits with code 1 if issues are found.
+func runLint(args []string, stdout, stderr io.Writer) (int, error) {
+	fs := newFlagSet("lint", "[directory]", stderr)
+	var (
+		format    = fs.String("format", "text", "output format: text, json or sarif")
+		rules     = fs.String("rules", "", "comma-separated rules to enable (default all rules)")
+		delimiter = fs.String("delimiter", "", "expected start delimiter (default the one used by most documents)")
+		quote     = fs.String("quote", "", "expected quote style: double or single (default the first style of each document)")
+		pattern   = fs.String("pattern", "*.md", "pattern matched against the document names")
+		html      = fs.Bool("html", false, "detect front matters in HTML comments")
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2, err
+	}
+
+	dir := "."
+	switch args = fs.Args(); len(args) {
+	case 0:
+	case 1:
+		dir = args[0]
+	default:
+		fs.Usage()
+		return 2, fmt.Errorf("expected at most 1 argument, got %d", len(args))
+	}
+
+	var write func(io.Writer, []lint.Issue) error
+	switch *format {
+	case "text":
+		write = writeIssues
+	case "json":
+		write = lint.WriteJSON
+	case "sarif":
+		write = lint.WriteSARIF
+	default:
+		return 2, fmt.Errorf("unknown output format %q", *format)
+	}
+	if *quote != "" && *quote != lint.QuoteDouble && *quote != lint.QuoteSingle {
+		return 2, fmt.Errorf("unknown quote style %q", *quote)
+	}
+
+	cfg := &lint.Config{
+		Delimiter: *delimiter,
+		Quote:     *quote,
+		Pattern:   *pattern,
+		Options:   &frontmatter.Options{HTMLComments: *html},
+	}
+	if *rules != "" {
+		for _, name := range strings.Split(*rules, ",") {
+			rule, err := parseRule(strings.TrimSpace(name))
+			if err != nil {
+				return 2, err
+			}
+			cfg.Rules = append(cfg.Rules, rule)
+		}
+	}
+
+	issues, err := lint.Lint(os.DirFS(dir), ".", cfg)
+	if err != nil {
+		return 2, err
+	}
+	if err := write(stdout, issues); err != nil {
+		return 2, err
+	}
+	if len(issues) > 0 {
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+func parseRule(name string) (lint.Rule, error) {
+	for _, rule := range lint.Rules {
+		if string(rule) == name {
+			return rule, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown rule %q", name)
+}
+
+func writeIssues(w io.Writer, issues []lint.Issue) error {
+	for _, issue := range issues {
+		if _, err := fmt.Fprintln(w, issue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
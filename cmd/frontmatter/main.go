@@ -0,0 +1,113 @@
+// Command frontmatter inspects the front matter of documents.
+//
+// Usage:
+//
+//	frontmatter <command> [flags] [arguments]
+//
+// The diff command reports the changes between the front matter fields of
+// two documents, regardless of their front matter formats. It can be used
+// as a git external diff driver or difftool:
+//
+//	GIT_EXTERNAL_DIFF='frontmatter diff' git diff
+//	git difftool -y -x 'frontmatter diff'
+//
+// The query command prints the documents of a directory whose front matter
+// matches an expression, as JSON lines or as a table. E.g.:
+//
+//	frontmatter query 'draft == true && !has(author)' content
+//	frontmatter query -format table -fields title,date '"go" in tags'
+//
+// The lint command checks the style and consistency of the front matter of
+// the documents of a directory, and exits with code 1 if issues are found.
+// The issues are printed as text, JSON or SARIF. E.g.:
+//
+//	frontmatter lint -rules sorted-keys,duplicate-keys content
+//	frontmatter lint -delimiter +++ -format sarif > lint.sarif
+//
+// Run `frontmatter <command> -h` for the flags of each command.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(args []string, stdout, stderr io.Writer) (int, error)
+}
+
+var commands = []*command{
+	{
+		name:  "diff",
+		usage: "report the front matter changes between two documents",
+		run:   runDiff,
+	},
+	{
+		name:  "query",
+		usage: "print the documents whose front matter matches an expression",
+		run:   runQuery,
+	},
+	{
+		name:  "lint",
+		usage: "check the style and consistency of front matters",
+		run:   runLint,
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+
+		code, err := cmd.run(args[1:], stdout, stderr)
+		if errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "frontmatter %s: %v\n", cmd.name, err)
+			if code == 0 {
+				code = 2
+			}
+		}
+		return code
+	}
+
+	fmt.Fprintf(stderr, "frontmatter: unknown command %q\n", args[0])
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: frontmatter <command> [flags] [arguments]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-8s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+// newFlagSet returns a flag set for the specified command, which reports
+// errors instead of exiting.
+func newFlagSet(name, args string, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: frontmatter %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	return fs
+}
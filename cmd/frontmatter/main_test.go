@@ -0,0 +1,235 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, data := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		args []string
+		code int
+		err  string
+	}{
+		{args: nil, code: 2, err: "Usage: frontmatter"},
+		{args: []string{"unknown"}, code: 2, err: `unknown command "unknown"`},
+		{args: []string{"diff", "-h"}, code: 2, err: "Usage: frontmatter diff"},
+		{args: []string{"diff", "a.md"}, code: 2, err: "expected 2 or 7 arguments"},
+		{args: []string{"diff", "missing.md", "missing.md"}, code: 2, err: "missing.md"},
+	}
+
+	for _, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+		if code := run(tc.args, &stdout, &stderr); code != tc.code {
+			t.Fatalf("%v: expected exit code %d, got %d", tc.args, tc.code, code)
+		}
+		if !strings.Contains(stderr.String(), tc.err) {
+			t.Fatalf("%v: expected error %q, got %q", tc.args, tc.err, stderr.String())
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"old.md": "---\ntitle: Post\ntags: [yaml]\ndate: 2024-01-02\n---\nbody",
+		"new.md": "+++\ntitle = \"New post\"\ntags = [\"yaml\", \"go\"]\n+++\nnew body",
+	})
+	oldName, newName := filepath.Join(dir, "old.md"), filepath.Join(dir, "new.md")
+
+	testCases := []struct {
+		args []string
+		code int
+		out  string
+	}{
+		{
+			args: []string{oldName, newName},
+			out: "--- " + oldName + "\n+++ " + newName + "\n" +
+				"date: removed \"2024-01-02\"\n" +
+				"tags: added \"go\"\n" +
+				"title: modified \"Post\" -> \"New post\"\n",
+		},
+		{
+			args: []string{"-exit-code", newName, newName},
+			out:  "",
+		},
+		{
+			args: []string{"-exit-code", "post.md", oldName, "abc", "100644", newName, "def", "100644"},
+			code: 1,
+			out: "--- a/post.md\n+++ b/post.md\n" +
+				"date: removed \"2024-01-02\"\n" +
+				"tags: added \"go\"\n" +
+				"title: modified \"Post\" -> \"New post\"\n",
+		},
+		{
+			args: []string{"-json", os.DevNull, newName},
+			out: `[
+  {
+    "type": "added",
+    "path": "tags",
+    "new": [
+      "yaml",
+      "go"
+    ]
+  },
+  {
+    "type": "added",
+    "path": "title",
+    "new": "New post"
+  }
+]
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+		if code := run(append([]string{"diff"}, tc.args...), &stdout, &stderr); code != tc.code {
+			t.Fatalf("%v: expected exit code %d, got %d: %s", tc.args, tc.code, code, stderr.String())
+		}
+		if stdout.String() != tc.out {
+			t.Fatalf("%v: expected output:\n%s\ngot:\n%s", tc.args, tc.out, stdout.String())
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.md":      "---\ntitle: A post\ndraft: true\ntags: [go, yaml]\ndate: 2024-01-02\n---\n",
+		"blog/b.md": "+++\ntitle = \"B\"\ndate = 2024-05-06\nauthor = \"John\"\n+++\n",
+		"blog/c.md": "no front matter",
+	})
+
+	testCases := []struct {
+		args []string
+		code int
+		out  string
+	}{
+		{
+			args: []string{`draft == true && !has(author)`, dir},
+			out:  `{"path":"a.md","matter":{"date":"2024-01-02","draft":true,"tags":["go","yaml"],"title":"A post"}}` + "\n",
+		},
+		{
+			args: []string{"-fields", "title,author", `date > 2024-01-01`, dir},
+			out: `{"path":"a.md","matter":{"title":"A post"}}` + "\n" +
+				`{"path":"blog/b.md","matter":{"author":"John","title":"B"}}` + "\n",
+		},
+		{
+			args: []string{"-format", "table", "-fields", "title,date,tags", "true", dir},
+			out: "PATH       TITLE   DATE        TAGS\n" +
+				"a.md       A post  2024-01-02  go, yaml\n" +
+				"blog/b.md  B       2024-05-06  -\n" +
+				"blog/c.md  -       -           -\n",
+		},
+		{
+			args: []string{"-format", "xml", "true", dir},
+			code: 2,
+		},
+		{
+			args: []string{"draft ==", dir},
+			code: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+		if code := run(append([]string{"query"}, tc.args...), &stdout, &stderr); code != tc.code {
+			t.Fatalf("%v: expected exit code %d, got %d: %s", tc.args, tc.code, code, stderr.String())
+		}
+		if stdout.String() != tc.out {
+			t.Fatalf("%v: expected output:\n%s\ngot:\n%s", tc.args, tc.out, stdout.String())
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.md":      "---\ntitle: A\ndate: 2024-01-02\n---\n",
+		"blog/b.md": "---\nauthor: John\ntitle: B\n---\n",
+		"blog/c.md": "+++\ntitle = \"C\"\n+++\n",
+	})
+
+	testCases := []struct {
+		args []string
+		code int
+		out  string
+	}{
+		{
+			args: []string{dir},
+			code: 1,
+			out: "a.md:3:1: key \"date\" should be sorted before \"title\" (sorted-keys)\n" +
+				"blog/c.md:1:1: delimiter \"+++\" should be \"---\" (delimiter)\n",
+		},
+		{
+			args: []string{"-rules", "empty,duplicate-keys", dir},
+		},
+		{
+			args: []string{"-format", "json", "-rules", "delimiter", "-delimiter", "+++", dir},
+			code: 1,
+			out: `[
+  {
+    "path": "a.md",
+    "line": 1,
+    "column": 1,
+    "rule": "delimiter",
+    "message": "delimiter \"---\" should be \"+++\""
+  },
+  {
+    "path": "blog/b.md",
+    "line": 1,
+    "column": 1,
+    "rule": "delimiter",
+    "message": "delimiter \"---\" should be \"+++\""
+  }
+]
+`,
+		},
+		{
+			args: []string{"-rules", "bogus", dir},
+			code: 2,
+		},
+		{
+			args: []string{"-format", "xml", dir},
+			code: 2,
+		},
+		{
+			args: []string{"-quote", "backtick", dir},
+			code: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+		if code := run(append([]string{"lint"}, tc.args...), &stdout, &stderr); code != tc.code {
+			t.Fatalf("%v: expected exit code %d, got %d: %s", tc.args, tc.code, code, stderr.String())
+		}
+		if stdout.String() != tc.out {
+			t.Fatalf("%v: expected output:\n%s\ngot:\n%s", tc.args, tc.out, stdout.String())
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"lint", "-format", "sarif", dir}, &stdout, &stderr); code != 1 ||
+		!strings.Contains(stdout.String(), `"ruleId": "sorted-keys"`) {
+		t.Fatalf("unexpected SARIF output (exit code %d):\n%s", code, stdout.String())
+	}
+}
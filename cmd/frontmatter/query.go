@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/query"
+)
+
+type jsonResult struct {
+	Path   string             `json:"path"`
+	Matter frontmatter.Matter `json:"matter"`
+}
+
+// runQuery prints the documents whose front matter matches a query.
+func runQuery(args []string, stdout, stderr io.Writer) (int, error) {
+	fs := newFlagSet("query", "<expression> [directory]", stderr)
+	var (
+		format  = fs.String("format", "jsonl", "output format: jsonl or table")
+		fields  = fs.String("fields", "", "comma-separated fields to output (default all fields for jsonl, title for table)")
+		pattern = fs.String("pattern", "*.md", "pattern matched against the document names")
+		html    = fs.Bool("html", false, "detect front matters in HTML comments")
+	)
+	if err := fs.Parse(args); err != nil {
+		return 2, err
+	}
+
+	dir := "."
+	switch args = fs.Args(); len(args) {
+	case 1:
+	case 2:
+		dir = args[1]
+	default:
+		fs.Usage()
+		return 2, fmt.Errorf("expected 1 or 2 arguments, got %d", len(args))
+	}
+	if *format != "jsonl" && *format != "table" {
+		return 2, fmt.Errorf("unknown output format %q", *format)
+	}
+
+	q, err := query.Parse(args[0])
+	if err != nil {
+		return 2, err
+	}
+
+	results, err := query.Find(os.DirFS(dir), ".", q, &query.Options{
+		Pattern: *pattern,
+		Parse:   &frontmatter.Options{HTMLComments: *html},
+	})
+	if err != nil {
+		return 2, err
+	}
+
+	var keys []string
+	if *fields != "" {
+		keys = strings.Split(*fields, ",")
+	}
+
+	if *format == "table" {
+		if keys == nil {
+			keys = []string{"title"}
+		}
+		return 0, writeTable(stdout, results, keys)
+	}
+
+	enc := json.NewEncoder(stdout)
+	for _, r := range results {
+		m := r.Matter
+		if keys != nil {
+			m = frontmatter.Matter{}
+			for _, key := range keys {
+				if v, ok := r.Matter.Get(key); ok {
+					m[key] = v
+				}
+			}
+		}
+
+		if err := enc.Encode(jsonResult{Path: r.Name, Matter: m}); err != nil {
+			return 2, err
+		}
+	}
+
+	return 0, nil
+}
+
+func writeTable(w io.Writer, results []query.Result, keys []string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprint(tw, "PATH")
+	for _, key := range keys {
+		fmt.Fprintf(tw, "\t%s", strings.ToUpper(key))
+	}
+	fmt.Fprintln(tw)
+
+	for _, r := range results {
+		fmt.Fprint(tw, r.Name)
+		for _, key := range keys {
+			v, _ := r.Matter.Get(key)
+			fmt.Fprintf(tw, "\t%s", formatCell(v))
+		}
+		fmt.Fprintln(tw)
+	}
+
+	return tw.Flush()
+}
+
+func formatCell(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return strings.Join(strings.Fields(t), " ")
+	case time.Time:
+		if t.Location().String() == "date-local" {
+			// TOML local dates.
+			return t.Format("2006-01-02")
+		}
+		return t.Format(time.RFC3339)
+	case []interface{}:
+		items := make([]string, len(t))
+		for i, item := range t {
+			items[i] = formatCell(item)
+		}
+		return strings.Join(items, ", ")
+	case map[string]interface{}:
+		data, err := json.Marshal(t)
+		if err != nil {
+			return fmt.Sprint(t)
+		}
+		return string(data)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,169 @@
+package frontmatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// defaultDateLayouts contains the layouts used to parse dates represented
+// as strings, in order of precedence. It includes RFC 3339, date-only and
+// other layouts commonly used by static site generators.
+var defaultDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -0700 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	"02 Jan 2006",
+	"2 January 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+}
+
+// DefaultDateLayouts returns the layouts used by default to parse dates
+// represented as strings, in order of precedence. It includes RFC 3339,
+// date-only and other layouts commonly used by static site generators.
+// The returned slice is a copy, which can be extended and passed to
+// `DateHook`.
+func DefaultDateLayouts() []string {
+	return append([]string(nil), defaultDateLayouts...)
+}
+
+// dateParser converts decoded values to times, using the specified layouts
+// and the specified location for dates which do not specify a time zone.
+type dateParser struct {
+	loc     *time.Location
+	layouts []string
+}
+
+// defaultDates parses dates using the default layouts and the UTC location.
+var defaultDates = dateParser{loc: time.UTC, layouts: defaultDateLayouts}
+
+// DateHook returns a decode hook which converts values decoded into
+// `time.Time` or `Date` fields using the specified location and layouts.
+// The location is used for dates which do not specify a time zone, such as
+// date-only values (e.g. `2006-01-02`) or TOML local dates. If the location
+// is nil, UTC is used. If no layouts are specified, the layouts returned by
+// `DefaultDateLayouts` are used.
+// E.g.: []DecodeHook{DateHook(time.Local)}.
+func DateHook(loc *time.Location, layouts ...string) DecodeHook {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if len(layouts) == 0 {
+		layouts = defaultDateLayouts
+	} else {
+		layouts = append([]string(nil), layouts...)
+	}
+	dp := dateParser{loc: loc, layouts: layouts}
+
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != timeType && to != dateType {
+			return data, nil
+		}
+
+		return dp.toTime(data)
+	}
+}
+
+// Date represents a front matter date. Unlike `time.Time`, it can be decoded
+// from any of the supported formats in the same way: native YAML and TOML
+// dates, as well as strings matching any of the `DefaultDateLayouts`. Dates
+// which do not specify a time zone use the UTC time zone, unless a
+// `DateHook` is used.
+type Date struct {
+	time.Time
+}
+
+// UnmarshalText decodes a date from the specified text.
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := defaultDates.parseTime(string(text))
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+	return nil
+}
+
+// UnmarshalJSON decodes a date from the specified JSON string.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("cannot decode %s as date", data)
+	}
+
+	return d.UnmarshalText([]byte(s))
+}
+
+// UnmarshalYAML decodes a date from a YAML timestamp or string.
+func (d *Date) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return d.set(v)
+}
+
+// UnmarshalTOML decodes a date from a TOML date or string.
+func (d *Date) UnmarshalTOML(v interface{}) error {
+	return d.set(v)
+}
+
+func (d *Date) set(v interface{}) error {
+	t, err := defaultDates.toTime(v)
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+	return nil
+}
+
+// toTime converts the specified decoded value to a time.
+func (dp dateParser) toTime(v interface{}) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		// Use the configured location for TOML local dates.
+		switch t.Location().String() {
+		case "date-local", "datetime-local", "time-local":
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(),
+				t.Minute(), t.Second(), t.Nanosecond(), dp.loc), nil
+		}
+		return t, nil
+	case Date:
+		return t.Time, nil
+	case string:
+		return dp.parseTime(t)
+	}
+
+	return time.Time{}, fmt.Errorf("cannot decode %T as date", v)
+}
+
+func (dp dateParser) parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dp.layouts {
+		if t, err := time.ParseInLocation(layout, s, dp.loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("cannot parse %q as date", s)
+}
@@ -0,0 +1,117 @@
+package frontmatter_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestDate(t *testing.T) {
+	type matter struct {
+		Date    frontmatter.Date `yaml:"date" toml:"date" json:"date"`
+		Updated frontmatter.Date `yaml:"updated" toml:"updated" json:"updated"`
+	}
+
+	loc := time.FixedZone("test", 2*60*60)
+	opts := &frontmatter.Options{
+		DecodeHooks: []frontmatter.DecodeHook{frontmatter.DateHook(loc)},
+	}
+
+	var (
+		date    = time.Date(2024, 1, 2, 0, 0, 0, 0, loc)
+		updated = time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	)
+
+	inputs := []string{
+		"---\ndate: 2024-01-02\nupdated: 2024-01-02T15:04:05Z\n---\n",
+		"---\ndate: \"2024-01-02\"\nupdated: \"2024-01-02 15:04:05 +0000\"\n---\n",
+		"+++\ndate = 2024-01-02\nupdated = 2024-01-02T15:04:05Z\n+++\n",
+		"+++\ndate = \"January 2, 2024\"\nupdated = \"Tue, 02 Jan 2024 15:04:05 +0000\"\n+++\n",
+		"{\n  \"date\": \"2024-01-02\",\n  \"updated\": \"2024-01-02T15:04:05Z\"\n}\n",
+		";;;\n{\"date\": \"2024/01/02\", \"updated\": \"2024-01-02T17:04:05+02:00\"}\n;;;\n",
+	}
+
+	for _, input := range inputs {
+		var m matter
+		if _, err := frontmatter.ParseDocument(strings.NewReader(input), &m, opts); err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if !m.Date.Equal(date) || !m.Updated.Equal(updated) {
+			t.Fatalf("Input: `%s`\n\nexpected dates %v and %v, got %v and %v",
+				input, date, updated, m.Date, m.Updated)
+		}
+
+		// Without hooks, dates which do not specify a time zone use UTC.
+		m = matter{}
+		if _, err := frontmatter.Parse(strings.NewReader(input), &m); err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if exp := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !m.Date.Equal(exp) || !m.Updated.Equal(updated) {
+			t.Fatalf("Input: `%s`\n\nexpected dates %v and %v, got %v and %v",
+				input, exp, updated, m.Date, m.Updated)
+		}
+	}
+
+	// Formats decoded using `frontmatter.Decode` support plain times as well.
+	var mmd struct {
+		Date    time.Time `yaml:"date"`
+		Updated time.Time `yaml:"updated"`
+	}
+	input := "Date: 02 Jan 2024\nUpdated: 2024-01-02T15:04:05Z\n\n"
+	mmdOpts := *opts
+	mmdOpts.Formats = []*frontmatter.Format{frontmatter.MultiMarkdownFormat()}
+	if _, err := frontmatter.ParseDocument(strings.NewReader(input), &mmd, &mmdOpts); err != nil {
+		t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+	}
+	if !mmd.Date.Equal(date) || !mmd.Updated.Equal(updated) {
+		t.Fatalf("Input: `%s`\n\nexpected dates %v and %v, got %v and %v",
+			input, date, updated, mmd.Date, mmd.Updated)
+	}
+
+	// TOML local dates use the configured location.
+	var m struct {
+		Date frontmatter.Date `toml:"date"`
+	}
+	if _, err := frontmatter.ParseDocument(strings.NewReader("+++\ndate = 2024-01-02T10:00:00\n+++\n"), &m, opts); err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2024, 1, 2, 10, 0, 0, 0, loc); !m.Date.Equal(exp) {
+		t.Fatalf("expected date %v, got %v", exp, m.Date)
+	}
+
+	// Custom layouts replace the default ones.
+	custom := &frontmatter.Options{
+		DecodeHooks: []frontmatter.DecodeHook{frontmatter.DateHook(nil, "02.01.2006")},
+	}
+	input = "---\ndate: 2024-01-02\n---\n"
+	if _, err := frontmatter.ParseDocument(strings.NewReader(input), &m, custom); err == nil {
+		t.Fatalf("Input: `%s`\n\nexpected error", input)
+	}
+	input = "---\ndate: 02.01.2024\n---\n"
+	if _, err := frontmatter.ParseDocument(strings.NewReader(input), &m, custom); err != nil {
+		t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+	}
+	if exp := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !m.Date.Equal(exp) {
+		t.Fatalf("Input: `%s`\n\nexpected date %v, got %v", input, exp, m.Date)
+	}
+
+	layouts := frontmatter.DefaultDateLayouts()
+	layouts[0] = ""
+	if frontmatter.DefaultDateLayouts()[0] != time.RFC3339Nano {
+		t.Fatal("expected default layouts to be copied")
+	}
+
+	// Invalid dates.
+	for _, input := range []string{
+		"---\ndate: yesterday\n---\n",
+		"+++\ndate = 42\n+++\n",
+		"{\n  \"date\": true\n}\n",
+	} {
+		var m matter
+		if _, err := frontmatter.Parse(strings.NewReader(input), &m); err == nil {
+			t.Fatalf("Input: `%s`\n\nexpected error", input)
+		}
+	}
+}
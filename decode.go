@@ -0,0 +1,456 @@
+package frontmatter
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	dateType = reflect.TypeOf(Date{})
+)
+
+// tagNames contains the struct tags used to map front matter keys to
+// struct fields, in order of precedence.
+var tagNames = []string{"yaml", "json", "toml"}
+
+// Decode stores the generic data in `src` into the value pointed to by `v`.
+// The data is expected to consist of maps, slices and scalar values, such
+// as the values produced by unmarshaling a front matter into an interface.
+// Map keys are matched against the `yaml`, `json` and `toml` struct tags of
+// the target fields, falling back to case-insensitive field name matching.
+// Scalar values are converted to the target type, if possible (e.g. the
+// string "10" can be decoded into an int field).
+func Decode(src interface{}, v interface{}) error {
+	return DecodeWithHooks(src, v)
+}
+
+// DecodeWithHooks is similar to `Decode`, but it calls the specified hooks
+// before decoding each value, allowing custom type conversions.
+func DecodeWithHooks(src interface{}, v interface{}, hooks ...DecodeHook) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("cannot decode into non-pointer %T", v)
+	}
+
+	d := &decoder{hooks: hooks}
+	return d.decodeValue(src, rv.Elem(), "")
+}
+
+type decoder struct {
+	hooks []DecodeHook
+}
+
+func (d *decoder) decodeValue(src interface{}, dst reflect.Value, path string) error {
+	for _, hook := range d.hooks {
+		if src == nil {
+			break
+		}
+
+		val, err := hook(reflect.TypeOf(src), dst.Type(), src)
+		if err != nil {
+			return decodeError(path, src, dst.Type(), err)
+		}
+		src = val
+	}
+	if src == nil {
+		return nil
+	}
+
+	// Decode times from any of the supported representations.
+	if typ := dst.Type(); typ == timeType || typ == dateType {
+		t, err := defaultDates.toTime(src)
+		if err != nil {
+			return decodeError(path, src, typ, err)
+		}
+
+		if typ == dateType {
+			dst.Set(reflect.ValueOf(Date{Time: t}))
+		} else {
+			dst.Set(reflect.ValueOf(t))
+		}
+		return nil
+	}
+
+	// Use text unmarshalers for string values.
+	if s, ok := src.(string); ok && dst.CanAddr() {
+		if u, ok := dst.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(s)); err != nil {
+				return decodeError(path, src, dst.Type(), err)
+			}
+			return nil
+		}
+	}
+
+	sv := reflect.ValueOf(src)
+	if dst.Kind() != reflect.Interface && sv.Type().AssignableTo(dst.Type()) &&
+		sv.Kind() != reflect.Map && sv.Kind() != reflect.Slice {
+		dst.Set(sv)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return d.decodeValue(src, dst.Elem(), path)
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			if !sv.Type().AssignableTo(dst.Type()) {
+				return decodeError(path, src, dst.Type(), nil)
+			}
+			dst.Set(sv)
+			return nil
+		}
+		dst.Set(reflect.ValueOf(normalize(src)))
+		return nil
+	case reflect.Struct:
+		return d.decodeStruct(src, dst, path)
+	case reflect.Map:
+		return d.decodeMap(src, dst, path)
+	case reflect.Slice, reflect.Array:
+		return d.decodeSlice(src, dst, path)
+	case reflect.String:
+		switch sv.Kind() {
+		case reflect.String, reflect.Bool, reflect.Int, reflect.Int8,
+			reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+			reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+			dst.SetString(fmt.Sprint(src))
+			return nil
+		}
+	case reflect.Bool:
+		switch sv.Kind() {
+		case reflect.Bool:
+			dst.SetBool(sv.Bool())
+			return nil
+		case reflect.String:
+			b, err := strconv.ParseBool(strings.TrimSpace(sv.String()))
+			if err != nil {
+				return decodeError(path, src, dst.Type(), err)
+			}
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch sv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return setInt(dst, sv.Int(), src, path)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return setInt(dst, int64(sv.Uint()), src, path)
+		case reflect.Float32, reflect.Float64:
+			if f := sv.Float(); f == float64(int64(f)) {
+				return setInt(dst, int64(f), src, path)
+			}
+		case reflect.String:
+			i, err := strconv.ParseInt(strings.TrimSpace(sv.String()), 0, 64)
+			if err != nil {
+				return decodeError(path, src, dst.Type(), err)
+			}
+			return setInt(dst, i, src, path)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch sv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if i := sv.Int(); i >= 0 {
+				return setUint(dst, uint64(i), src, path)
+			}
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return setUint(dst, sv.Uint(), src, path)
+		case reflect.Float32, reflect.Float64:
+			if f := sv.Float(); f >= 0 && f == float64(uint64(f)) {
+				return setUint(dst, uint64(f), src, path)
+			}
+		case reflect.String:
+			u, err := strconv.ParseUint(strings.TrimSpace(sv.String()), 0, 64)
+			if err != nil {
+				return decodeError(path, src, dst.Type(), err)
+			}
+			return setUint(dst, u, src, path)
+		}
+	case reflect.Float32, reflect.Float64:
+		switch sv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			dst.SetFloat(float64(sv.Int()))
+			return nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			dst.SetFloat(float64(sv.Uint()))
+			return nil
+		case reflect.Float32, reflect.Float64:
+			dst.SetFloat(sv.Float())
+			return nil
+		case reflect.String:
+			f, err := strconv.ParseFloat(strings.TrimSpace(sv.String()), 64)
+			if err != nil {
+				return decodeError(path, src, dst.Type(), err)
+			}
+			dst.SetFloat(f)
+			return nil
+		}
+	}
+
+	if sv.Type().ConvertibleTo(dst.Type()) && sv.Kind() == dst.Kind() {
+		dst.Set(sv.Convert(dst.Type()))
+		return nil
+	}
+
+	return decodeError(path, src, dst.Type(), nil)
+}
+
+func (d *decoder) decodeStruct(src interface{}, dst reflect.Value, path string) error {
+	m, ok := normalize(src).(map[string]interface{})
+	if !ok {
+		return decodeError(path, src, dst.Type(), nil)
+	}
+
+	fields := structFields(dst.Type())
+	for key, val := range m {
+		field, ok := fields[key]
+		if !ok {
+			if field, ok = fields[strings.ToLower(key)]; !ok {
+				continue
+			}
+		}
+
+		fv := fieldByIndex(dst, field.index)
+		if err := d.decodeValue(val, fv, joinPath(path, key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeMap(src interface{}, dst reflect.Value, path string) error {
+	m, ok := normalize(src).(map[string]interface{})
+	if !ok {
+		return decodeError(path, src, dst.Type(), nil)
+	}
+	if dst.IsNil() {
+		dst.Set(reflect.MakeMapWithSize(dst.Type(), len(m)))
+	}
+
+	typ := dst.Type()
+	for key, val := range m {
+		kv := reflect.New(typ.Key()).Elem()
+		if err := d.decodeValue(key, kv, path); err != nil {
+			return err
+		}
+
+		vv := reflect.New(typ.Elem()).Elem()
+		if existing := dst.MapIndex(kv); existing.IsValid() {
+			vv.Set(existing)
+		}
+		if err := d.decodeValue(val, vv, joinPath(path, key)); err != nil {
+			return err
+		}
+		dst.SetMapIndex(kv, vv)
+	}
+
+	return nil
+}
+
+func (d *decoder) decodeSlice(src interface{}, dst reflect.Value, path string) error {
+	sv := reflect.ValueOf(src)
+	if sv.Kind() != reflect.Slice && sv.Kind() != reflect.Array {
+		// Decode scalar values as single element slices.
+		if sv.Kind() == reflect.Map {
+			return decodeError(path, src, dst.Type(), nil)
+		}
+		sv = reflect.ValueOf([]interface{}{src})
+	}
+
+	n := sv.Len()
+	if dst.Kind() == reflect.Array {
+		if n > dst.Len() {
+			return decodeError(path, src, dst.Type(), nil)
+		}
+	} else {
+		dst.Set(reflect.MakeSlice(dst.Type(), n, n))
+	}
+
+	for i := 0; i < n; i++ {
+		p := path + "[" + strconv.Itoa(i) + "]"
+		if err := d.decodeValue(sv.Index(i).Interface(), dst.Index(i), p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setInt(dst reflect.Value, i int64, src interface{}, path string) error {
+	if dst.OverflowInt(i) {
+		return decodeError(path, src, dst.Type(), nil)
+	}
+
+	dst.SetInt(i)
+	return nil
+}
+
+func setUint(dst reflect.Value, u uint64, src interface{}, path string) error {
+	if dst.OverflowUint(u) {
+		return decodeError(path, src, dst.Type(), nil)
+	}
+
+	dst.SetUint(u)
+	return nil
+}
+
+type structField struct {
+	index []int
+}
+
+// structFields returns the decodable fields of the specified struct type,
+// indexed by their key. Embedded structs and fields tagged as `inline` are
+// flattened. Lowercase keys are added for case-insensitive matching.
+func structFields(typ reflect.Type) map[string]structField {
+	fields := map[string]structField{}
+	folded := map[string]structField{}
+
+	var walk func(typ reflect.Type, index []int)
+	walk = func(typ reflect.Type, index []int) {
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue
+			}
+
+			name, inline, skip := fieldTag(f)
+			if skip {
+				continue
+			}
+
+			idx := append(append([]int{}, index...), i)
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if (inline || (f.Anonymous && name == "")) && ft.Kind() == reflect.Struct {
+				walk(ft, idx)
+				continue
+			}
+			if f.PkgPath != "" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+
+			if _, ok := fields[name]; !ok {
+				fields[name] = structField{index: idx}
+			}
+			if key := strings.ToLower(name); key != name {
+				if _, ok := folded[key]; !ok {
+					folded[key] = structField{index: idx}
+				}
+			}
+		}
+	}
+	walk(typ, nil)
+
+	for key, field := range folded {
+		if _, ok := fields[key]; !ok {
+			fields[key] = field
+		}
+	}
+
+	return fields
+}
+
+func fieldTag(f reflect.StructField) (string, bool, bool) {
+	for _, tagName := range tagNames {
+		tag, ok := f.Tag.Lookup(tagName)
+		if !ok {
+			continue
+		}
+		if tag == "-" {
+			return "", false, true
+		}
+
+		name, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, opts = tag[:i], tag[i:]
+		}
+		inline := strings.Contains(opts, ",inline") || strings.Contains(opts, ",squash")
+		return name, inline, false
+	}
+
+	return "", false, false
+}
+
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// normalize returns a copy of the specified value, in which the maps are
+// converted to maps with string keys. Some decoders (e.g. yaml.v2) produce
+// maps having interface keys. The specified value is not modified.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[fmt.Sprint(key)] = normalize(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[key] = normalize(val)
+		}
+		return m
+	case Matter:
+		return normalize(map[string]interface{}(t))
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = normalize(val)
+		}
+		return s
+	case []map[string]interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = normalize(val)
+		}
+		return s
+	}
+
+	return v
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+func decodeError(path string, src interface{}, typ reflect.Type, err error) error {
+	msg := fmt.Sprintf("cannot decode %T into %s", src, typ)
+	if path != "" {
+		msg = path + ": " + msg
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+
+	return errors.New(msg)
+}
@@ -0,0 +1,124 @@
+package frontmatter_test
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestDecode(t *testing.T) {
+	type (
+		base struct {
+			ID string `json:"id"`
+		}
+
+		matter struct {
+			base     `yaml:",inline"`
+			Name     string            `yaml:"name"`
+			Size     int               `toml:"size"`
+			Ratio    float64           `json:"ratio"`
+			Enabled  bool              `yaml:"enabled"`
+			Tags     []string          `yaml:"tags"`
+			Labels   map[string]string `yaml:"labels"`
+			IP       net.IP            `yaml:"ip"`
+			Parent   *matter           `yaml:"parent"`
+			Extra    interface{}       `yaml:"extra"`
+			Title    string
+			Ignored  string `yaml:"-"`
+			internal string
+		}
+	)
+
+	src := map[interface{}]interface{}{
+		"id":      "doc",
+		"name":    "frontmatter",
+		"size":    "10",
+		"ratio":   1,
+		"enabled": "true",
+		"tags":    "go",
+		"labels":  map[interface{}]interface{}{"lang": "go"},
+		"ip":      "127.0.0.1",
+		"parent":  map[string]interface{}{"name": "parent", "size": 5},
+		"extra":   map[interface{}]interface{}{"key": []interface{}{1, "two"}},
+		"TITLE":   "title",
+		"Ignored": "ignored",
+	}
+
+	var act matter
+	if err := frontmatter.Decode(src, &act); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := matter{
+		base:    base{ID: "doc"},
+		Name:    "frontmatter",
+		Size:    10,
+		Ratio:   1,
+		Enabled: true,
+		Tags:    []string{"go"},
+		Labels:  map[string]string{"lang": "go"},
+		IP:      net.ParseIP("127.0.0.1"),
+		Parent:  &matter{Name: "parent", Size: 5},
+		Extra:   map[string]interface{}{"key": []interface{}{1, "two"}},
+		Title:   "title",
+	}
+	if !reflect.DeepEqual(exp, act) {
+		t.Fatalf("Not equal:\nexpected: %+v\nactual  : %+v", exp, act)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var m struct {
+		Size  int8     `yaml:"size"`
+		Tags  []string `yaml:"tags"`
+		Inner struct {
+			Enabled bool `yaml:"enabled"`
+		} `yaml:"inner"`
+	}
+
+	testCases := []interface{}{
+		map[string]interface{}{"size": "ten"},
+		map[string]interface{}{"size": 1000},
+		map[string]interface{}{"tags": map[string]interface{}{}},
+		map[string]interface{}{"inner": "text"},
+		map[string]interface{}{"inner": map[string]interface{}{"enabled": "maybe"}},
+		[]interface{}{"size"},
+	}
+
+	for _, src := range testCases {
+		if err := frontmatter.Decode(src, &m); err == nil {
+			t.Fatalf("expected error when decoding %v", src)
+		}
+	}
+	if err := frontmatter.Decode(map[string]interface{}{}, m); err == nil {
+		t.Fatal("expected error when decoding into non-pointer value")
+	}
+}
+
+func TestDecodeNoMutation(t *testing.T) {
+	newSrc := func() map[string]interface{} {
+		return map[string]interface{}{
+			"author": map[interface{}]interface{}{"name": "John"},
+			"posts":  []interface{}{map[interface{}]interface{}{1: "one"}},
+		}
+	}
+
+	src := newSrc()
+	var dst interface{}
+	if err := frontmatter.Decode(src, &dst); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := newSrc(); !reflect.DeepEqual(exp, src) {
+		t.Fatalf("Not equal:\nexpected: %+v\nactual  : %+v", exp, src)
+	}
+
+	exp := map[string]interface{}{
+		"author": map[string]interface{}{"name": "John"},
+		"posts":  []interface{}{map[string]interface{}{"1": "one"}},
+	}
+	if !reflect.DeepEqual(exp, dst) {
+		t.Fatalf("Not equal:\nexpected: %+v\nactual  : %+v", exp, dst)
+	}
+}
@@ -0,0 +1,198 @@
+package frontmatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+)
+
+// FieldChangeType defines the type of a front matter field change.
+type FieldChangeType int
+
+// Front matter field change types.
+const (
+	// FieldAdded reports a field which was added.
+	FieldAdded FieldChangeType = iota + 1
+
+	// FieldRemoved reports a field which was removed.
+	FieldRemoved
+
+	// FieldModified reports a field whose value changed.
+	FieldModified
+)
+
+// String returns the name of the change type.
+func (t FieldChangeType) String() string {
+	switch t {
+	case FieldAdded:
+		return "added"
+	case FieldRemoved:
+		return "removed"
+	case FieldModified:
+		return "modified"
+	}
+
+	return "unknown"
+}
+
+// FieldChange describes a change of a front matter field.
+type FieldChange struct {
+	// Type is the type of the change.
+	Type FieldChangeType
+
+	// Path is the dotted path of the field (e.g. `author.name`).
+	Path string
+
+	// Old is the old value of the field. It is nil for added fields.
+	Old interface{}
+
+	// New is the new value of the field. It is nil for removed fields.
+	New interface{}
+}
+
+// Diff parses the front matter of the specified documents and returns the
+// changes of their fields, as returned by `DiffMatter`. Documents which do
+// not contain a front matter are treated as having an empty front matter.
+func Diff(a, b io.Reader, opts *Options) ([]FieldChange, error) {
+	ma, err := parseMatter(a, opts)
+	if err != nil {
+		return nil, err
+	}
+	mb, err := parseMatter(b, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffMatter(ma, mb), nil
+}
+
+// DiffMatter returns the changes of the fields of the specified front
+// matters, sorted by path. Nested maps are compared recursively, while
+// other values are compared using their canonical representation (e.g.
+// the integer 1 and the float 1.0 are equal). The items added to or
+// removed from a list are reported individually, using the path of the
+// list. Lists containing the same items in a different order are reported
+// as modified.
+func DiffMatter(a, b Matter) []FieldChange {
+	changes := diffMaps("", clone(map[string]interface{}(a)).(map[string]interface{}),
+		clone(map[string]interface{}(b)).(map[string]interface{}), nil)
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+// String returns a textual representation of the change.
+// E.g.: `tags: added "go"` or `title: modified "Post" -> "New post"`.
+func (c FieldChange) String() string {
+	switch c.Type {
+	case FieldAdded:
+		return fmt.Sprintf("%s: added %s", c.Path, formatValue(c.New))
+	case FieldRemoved:
+		return fmt.Sprintf("%s: removed %s", c.Path, formatValue(c.Old))
+	}
+
+	return fmt.Sprintf("%s: %s %s -> %s", c.Path, c.Type, formatValue(c.Old), formatValue(c.New))
+}
+
+// parseMatter parses the front matter of the specified document.
+func parseMatter(r io.Reader, opts *Options) (Matter, error) {
+	var v interface{}
+	if _, err := ParseDocument(r, &v, opts); err != nil {
+		return nil, err
+	}
+
+	return newMatter(v)
+}
+
+func formatValue(v interface{}) string {
+	data, err := json.Marshal(canonical(clone(v)))
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
+
+func diffMaps(path string, a, b map[string]interface{}, changes []FieldChange) []FieldChange {
+	for key, old := range a {
+		p := joinPath(path, key)
+		val, ok := b[key]
+		if !ok {
+			changes = append(changes, FieldChange{Type: FieldRemoved, Path: p, Old: old})
+			continue
+		}
+
+		if equalValues(old, val) {
+			continue
+		}
+
+		om, ok1 := old.(map[string]interface{})
+		nm, ok2 := val.(map[string]interface{})
+		if ok1 && ok2 {
+			changes = diffMaps(p, om, nm, changes)
+			continue
+		}
+
+		ol, ok1 := old.([]interface{})
+		nl, ok2 := val.([]interface{})
+		if ok1 && ok2 {
+			if items := diffLists(p, ol, nl); len(items) > 0 {
+				changes = append(changes, items...)
+				continue
+			}
+		}
+
+		changes = append(changes, FieldChange{Type: FieldModified, Path: p, Old: old, New: val})
+	}
+	for key, val := range b {
+		if _, ok := a[key]; !ok {
+			changes = append(changes, FieldChange{Type: FieldAdded, Path: joinPath(path, key), New: val})
+		}
+	}
+
+	return changes
+}
+
+// diffLists returns the items removed from and added to the specified
+// lists. If the lists contain the same items, no changes are returned.
+func diffLists(path string, a, b []interface{}) []FieldChange {
+	used := make([]bool, len(b))
+
+	var changes []FieldChange
+	for _, old := range a {
+		found := false
+		for i, val := range b {
+			if !used[i] && equalValues(old, val) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			changes = append(changes, FieldChange{Type: FieldRemoved, Path: path, Old: old})
+		}
+	}
+	for i, val := range b {
+		if !used[i] {
+			changes = append(changes, FieldChange{Type: FieldAdded, Path: path, New: val})
+		}
+	}
+
+	return changes
+}
+
+// equalValues reports whether the specified values are equal, regardless
+// of the format used to decode them (e.g. the integer 1 and the float 1.0
+// are equal).
+func equalValues(a, b interface{}) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+
+	ja, err1 := json.Marshal(canonical(clone(a)))
+	jb, err2 := json.Marshal(canonical(clone(b)))
+	return err1 == nil && err2 == nil && string(ja) == string(jb)
+}
@@ -0,0 +1,87 @@
+package frontmatter_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestDiff(t *testing.T) {
+	a := `---
+title: Post
+date: 2024-01-02
+tags: [yaml, toml]
+weight: 1
+order: [a, b]
+author:
+  name: John Doe
+  email: john@example.com
+---
+rest of the file`
+
+	b := `+++
+title = "New post"
+tags = ["toml", "go"]
+weight = 1.0
+order = ["b", "a"]
+draft = true
+
+[author]
+name = "John Doe"
+twitter = "@john"
++++
+rest of the file`
+
+	exp := []frontmatter.FieldChange{
+		{Type: frontmatter.FieldRemoved, Path: "author.email", Old: "john@example.com"},
+		{Type: frontmatter.FieldAdded, Path: "author.twitter", New: "@john"},
+		{Type: frontmatter.FieldRemoved, Path: "date", Old: "2024-01-02"},
+		{Type: frontmatter.FieldAdded, Path: "draft", New: true},
+		{Type: frontmatter.FieldModified, Path: "order", Old: []interface{}{"a", "b"}, New: []interface{}{"b", "a"}},
+		{Type: frontmatter.FieldRemoved, Path: "tags", Old: "yaml"},
+		{Type: frontmatter.FieldAdded, Path: "tags", New: "go"},
+		{Type: frontmatter.FieldModified, Path: "title", Old: "Post", New: "New post"},
+	}
+	expStrings := []string{
+		`author.email: removed "john@example.com"`,
+		`author.twitter: added "@john"`,
+		`date: removed "2024-01-02"`,
+		`draft: added true`,
+		`order: modified ["a","b"] -> ["b","a"]`,
+		`tags: removed "yaml"`,
+		`tags: added "go"`,
+		`title: modified "Post" -> "New post"`,
+	}
+
+	changes, err := frontmatter.Diff(strings.NewReader(a), strings.NewReader(b), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(changes, exp) {
+		t.Fatalf("expected changes %+v, got %+v", exp, changes)
+	}
+	for i, change := range changes {
+		if change.String() != expStrings[i] {
+			t.Fatalf("expected change %q, got %q", expStrings[i], change.String())
+		}
+	}
+
+	// Identical front matters, regardless of format.
+	c := "{\n  \"weight\": 1, \"order\": [\"b\", \"a\"], \"title\": \"New post\", \"draft\": true,\n" +
+		"  \"tags\": [\"toml\", \"go\"], \"author\": {\"twitter\": \"@john\", \"name\": \"John Doe\"}\n}\n"
+	if changes, err = frontmatter.Diff(strings.NewReader(b), strings.NewReader(c), nil); err != nil || len(changes) != 0 {
+		t.Fatalf("unexpected changes: %v %v", changes, err)
+	}
+
+	// Documents without front matter.
+	if changes, err = frontmatter.Diff(strings.NewReader("body"), strings.NewReader(b), nil); err != nil || len(changes) != 6 {
+		t.Fatalf("unexpected changes: %v %v", changes, err)
+	}
+
+	// Invalid front matter.
+	if _, err = frontmatter.Diff(strings.NewReader(a), strings.NewReader("---\n- item\n---\n"), nil); err == nil {
+		t.Fatal("expected error for invalid front matter")
+	}
+}
@@ -0,0 +1,143 @@
+package frontmatter
+
+import (
+	"bytes"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExcerptOptions holds the configuration used to extract the excerpt of
+// a document body.
+type ExcerptOptions struct {
+	// Separators defines the markers separating the excerpt from the rest
+	// of the body. The first marker found in the body is used. If no
+	// separators are provided, `<!--more-->`, `<!-- more -->`,
+	// `<!--excerpt-->` and `<!-- excerpt -->` are used.
+	Separators []string
+
+	// Words defines the number of words of the excerpt, if the body does
+	// not contain any of the separators. If 0, the first paragraph of the
+	// body is used instead.
+	Words int
+}
+
+// DefaultWordsPerMinute is the reading speed used by `ReadingTime`, if none
+// is specified.
+const DefaultWordsPerMinute = 200
+
+// Excerpt returns the excerpt of the document body, and reports whether the
+// excerpt is shorter than the body. The excerpt consists of the content
+// preceding the first separator, if the body contains one. Otherwise, it
+// consists of the first words, or of the first paragraph of the body,
+// based on the provided options. The leading and trailing whitespace of the
+// excerpt is removed. The returned excerpt references the body data.
+func (d *Document) Excerpt(opts *ExcerptOptions) ([]byte, bool) {
+	if opts == nil {
+		opts = &ExcerptOptions{}
+	}
+
+	separators := opts.Separators
+	if len(separators) == 0 {
+		separators = []string{"<!--more-->", "<!-- more -->", "<!--excerpt-->", "<!-- excerpt -->"}
+	}
+
+	// Use the content preceding the first separator.
+	body, pos := d.Body, -1
+	for _, sep := range separators {
+		if i := bytes.Index(body, []byte(sep)); i >= 0 && (pos < 0 || i < pos) {
+			pos = i
+		}
+	}
+	if pos >= 0 {
+		return bytes.TrimSpace(body[:pos]), true
+	}
+
+	// Use the first words of the body.
+	body = bytes.TrimSpace(body)
+	if opts.Words > 0 {
+		end, n := 0, 0
+		for i := 0; i < len(body) && n < opts.Words; n++ {
+			_, end = nextWord(body, i)
+			i = end
+		}
+
+		excerpt := body[:end]
+		return excerpt, len(excerpt) < len(body)
+	}
+
+	// Use the first paragraph of the body.
+	for i := 0; i < len(body); {
+		j := bytes.IndexByte(body[i:], '\n')
+		if j < 0 {
+			break
+		}
+		i += j + 1
+
+		line := body[i:]
+		if k := bytes.IndexByte(line, '\n'); k >= 0 {
+			line = line[:k]
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			return bytes.TrimSpace(body[:i]), true
+		}
+	}
+
+	return body, false
+}
+
+// WordCount returns the number of words of the document body. Words are
+// separated by whitespace and must contain at least a letter or a digit.
+func (d *Document) WordCount() int {
+	var n int
+	for i := 0; i < len(d.Body); {
+		start, end := nextWord(d.Body, i)
+		if start == end {
+			break
+		}
+		if bytes.IndexFunc(d.Body[start:end], isAlnum) >= 0 {
+			n++
+		}
+		i = end
+	}
+
+	return n
+}
+
+func isAlnum(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// ReadingTime returns the estimated time required to read the document
+// body, based on the specified reading speed, in words per minute.
+// If the speed is not positive, `DefaultWordsPerMinute` is used.
+func (d *Document) ReadingTime(wordsPerMinute int) time.Duration {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+
+	return time.Duration(d.WordCount()) * time.Minute / time.Duration(wordsPerMinute)
+}
+
+// nextWord returns the bounds of the first word of the data, starting at
+// the specified offset. If the data does not contain any words, both
+// bounds are equal to the length of the data.
+func nextWord(data []byte, offset int) (int, int) {
+	start := -1
+	for i := offset; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				return start, i
+			}
+		} else if start < 0 {
+			start = i
+		}
+		i += size
+	}
+
+	if start < 0 {
+		return len(data), len(data)
+	}
+	return start, len(data)
+}
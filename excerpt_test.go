@@ -0,0 +1,103 @@
+package frontmatter_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestExcerpt(t *testing.T) {
+	testCases := []struct {
+		body      string
+		opts      *frontmatter.ExcerptOptions
+		excerpt   string
+		truncated bool
+	}{
+		{
+			body:      "\nFirst paragraph\nstill first.\n\nSecond <!--more--> paragraph.\n",
+			excerpt:   "First paragraph\nstill first.\n\nSecond",
+			truncated: true,
+		},
+		{
+			body:      "Intro\n<!-- excerpt -->\nRest <!--more-->\n",
+			excerpt:   "Intro",
+			truncated: true,
+		},
+		{
+			body:      "Intro\n<!-- cut -->\nRest\n",
+			opts:      &frontmatter.ExcerptOptions{Separators: []string{"<!-- cut -->"}},
+			excerpt:   "Intro",
+			truncated: true,
+		},
+		{
+			body:      "\n\nFirst paragraph\nstill first.\n  \nSecond paragraph.\n",
+			excerpt:   "First paragraph\nstill first.",
+			truncated: true,
+		},
+		{
+			body:    "Single paragraph.\n\n\n",
+			excerpt: "Single paragraph.",
+		},
+		{
+			body:      "  One two\tthree,\n\nfour five.",
+			opts:      &frontmatter.ExcerptOptions{Words: 4},
+			excerpt:   "One two\tthree,\n\nfour",
+			truncated: true,
+		},
+		{
+			body:    "One two three.\n",
+			opts:    &frontmatter.ExcerptOptions{Words: 3},
+			excerpt: "One two three.",
+		},
+		{
+			body: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		input := "---\ntitle: frontmatter\n---\n" + tc.body
+
+		var m interface{}
+		doc, err := frontmatter.ParseDocument(strings.NewReader(input), &m, nil)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+
+		excerpt, truncated := doc.Excerpt(tc.opts)
+		if string(excerpt) != tc.excerpt || truncated != tc.truncated {
+			t.Fatalf("Input: `%s`\n\nexpected excerpt %q (%t), got %q (%t)",
+				input, tc.excerpt, tc.truncated, excerpt, truncated)
+		}
+	}
+}
+
+func TestWordCount(t *testing.T) {
+	testCases := []struct {
+		body    string
+		words   int
+		reading time.Duration
+	}{
+		{"", 0, 0},
+		{" \n\t ", 0, 0},
+		{"one", 1, 300 * time.Millisecond},
+		{"  one\ttwo\n\nthree — four five ", 5, 1500 * time.Millisecond},
+		{strings.Repeat("word ", 400), 400, 2 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		doc := &frontmatter.Document{Body: []byte(tc.body)}
+		if words := doc.WordCount(); words != tc.words {
+			t.Fatalf("Input: `%s`\n\nexpected %d words, got %d", tc.body, tc.words, words)
+		}
+		if reading := doc.ReadingTime(0); reading != tc.reading {
+			t.Fatalf("Input: `%s`\n\nexpected reading time %v, got %v", tc.body, tc.reading, reading)
+		}
+	}
+
+	doc := &frontmatter.Document{Body: []byte(strings.Repeat("word ", 300))}
+	if reading := doc.ReadingTime(100); reading != 3*time.Minute {
+		t.Fatalf("expected reading time %v, got %v", 3*time.Minute, reading)
+	}
+}
@@ -0,0 +1,130 @@
+package frontmatter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// dataUnmarshalers contains the default unmarshal functions of data files,
+// keyed by extension.
+var dataUnmarshalers = map[string]UnmarshalFunc{
+	".yaml": yaml.Unmarshal,
+	".yml":  yaml.Unmarshal,
+	".toml": toml.Unmarshal,
+	".json": json.Unmarshal,
+}
+
+// ParseFile parses the front matter of the specified file of the file
+// system, and returns it along with the parsed document. Documents without
+// front matter have an empty front matter. The decode hooks of the provided
+// options are not used, and the includes are resolved relative to the file.
+func ParseFile(fsys fs.FS, name string, opts *Options) (Matter, *Document, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var o Options
+	if opts != nil {
+		o = *opts
+		o.DecodeHooks = nil
+	}
+
+	// Resolve the includes relative to the file.
+	if o.Includes != nil {
+		inc := *o.Includes
+		if inc.FS == nil {
+			inc.FS = fsys
+		}
+		inc.Path = name
+		o.Includes = &inc
+	}
+
+	var v interface{}
+	doc, err := ParseDocument(f, &v, &o)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	m, err := newMatter(v)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return m, doc, nil
+}
+
+// parseFile is similar to `ParseFile`, but it returns the body of the
+// document instead of the document.
+func parseFile(fsys fs.FS, opts *Options, name string) (Matter, []byte, error) {
+	m, doc, err := ParseFile(fsys, name, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m, doc.Body, nil
+}
+
+// Walk calls fn for each file found in the specified directory and its
+// subdirectories, in lexical order, whose base name matches the specified
+// pattern, using the syntax of `path.Match`. If the pattern is empty, `*.md`
+// is used. If fn returns an error, the walk is stopped and the error is
+// returned.
+func Walk(fsys fs.FS, root, pattern string, fn func(name string, d fs.DirEntry) error) error {
+	if pattern == "" {
+		pattern = "*.md"
+	}
+
+	return fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if ok, err := path.Match(pattern, path.Base(name)); err != nil || !ok {
+			return err
+		}
+
+		return fn(name, d)
+	})
+}
+
+// readData decodes the specified data file, using the unmarshal function
+// registered for its extension. If the file does not exist, a nil front
+// matter is returned.
+func readData(fsys fs.FS, unmarshalers map[string]UnmarshalFunc, name string) (Matter, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if unmarshalers == nil {
+		unmarshalers = dataUnmarshalers
+	}
+
+	unmarshal, ok := unmarshalers[strings.ToLower(path.Ext(name))]
+	if !ok {
+		return nil, fmt.Errorf("%s: unsupported file extension", name)
+	}
+
+	var v interface{}
+	if err := unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	m, err := newMatter(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,86 @@
+package frontmatter_test
+
+import (
+	"errors"
+	"io/fs"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestParseFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		"post.md":         {Data: []byte("---\ntitle: Post\n$include: shared/seo.yaml\n---\nbody")},
+		"plain.md":        {Data: []byte("no front matter")},
+		"invalid.md":      {Data: []byte("---\ntitle: [\n---\n")},
+		"shared/seo.yaml": {Data: []byte("description: SEO")},
+	}
+	opts := &frontmatter.Options{Includes: &frontmatter.Includes{}}
+
+	m, doc, err := frontmatter.ParseFile(fsys, "post.md", opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if exp := (frontmatter.Matter{"title": "Post", "description": "SEO"}); !reflect.DeepEqual(m, exp) {
+		t.Fatalf("expected %v, got %v", exp, m)
+	}
+	if doc.Format == nil || doc.Format.Start != "---" || string(doc.Body) != "body" {
+		t.Fatalf("unexpected document: %+v", doc)
+	}
+
+	if m, doc, err = frontmatter.ParseFile(fsys, "plain.md", nil); err != nil || len(m) != 0 || doc.Format != nil {
+		t.Fatalf("unexpected result: %v %+v %v", m, doc, err)
+	}
+	if _, _, err = frontmatter.ParseFile(fsys, "invalid.md", nil); err == nil || !strings.HasPrefix(err.Error(), "invalid.md: ") {
+		t.Fatalf("expected error prefixed by file name, got %v", err)
+	}
+	if _, _, err = frontmatter.ParseFile(fsys, "missing.md", nil); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected not exist error, got %v", err)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	fsys := fstest.MapFS{
+		"b.md":        {},
+		"a.md":        {},
+		"notes.txt":   {},
+		"blog/c.md":   {},
+		"blog/d.html": {},
+		"docs/e.md":   {},
+	}
+
+	walk := func(root, pattern string) ([]string, error) {
+		var names []string
+		err := frontmatter.Walk(fsys, root, pattern, func(name string, d fs.DirEntry) error {
+			if d.IsDir() {
+				t.Fatalf("unexpected directory %s", name)
+			}
+			names = append(names, name)
+			return nil
+		})
+		return names, err
+	}
+
+	if names, err := walk(".", ""); err != nil || !reflect.DeepEqual(names, []string{"a.md", "b.md", "blog/c.md", "docs/e.md"}) {
+		t.Fatalf("unexpected result: %v %v", names, err)
+	}
+	if names, err := walk("blog", "*.html"); err != nil || !reflect.DeepEqual(names, []string{"blog/d.html"}) {
+		t.Fatalf("unexpected result: %v %v", names, err)
+	}
+	if _, err := walk(".", "["); err == nil {
+		t.Fatal("expected error for invalid pattern")
+	}
+
+	errStop := errors.New("stop")
+	var count int
+	err := frontmatter.Walk(fsys, ".", "", func(name string, d fs.DirEntry) error {
+		count++
+		return errStop
+	})
+	if err != errStop || count != 1 {
+		t.Fatalf("expected walk to stop after the first error, got %v after %d files", err, count)
+	}
+}
@@ -1,7 +1,11 @@
 package frontmatter
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
 
 	"github.com/BurntSushi/toml"
 	"gopkg.in/yaml.v2"
@@ -36,6 +40,48 @@ type Format struct {
 	// required after the front matter.
 	// Should be `false` in most cases.
 	RequiresNewLine bool
+
+	// Prefix defines the line prefix of front matters embedded in source
+	// code comments. If set, the prefix is stripped from the delimiter
+	// lines and from every line of the front matter before decoding.
+	// If the start and end delimiters are empty, the format describes a
+	// header front matter, consisting of the contiguous lines starting
+	// with the prefix. E.g.: `//`, `#` or `#+` (for Org mode keywords).
+	Prefix string
+
+	// Inline specifies whether the front matter can be contained in a
+	// single line, enclosed by the start and end delimiters. Inline front
+	// matters must contain at least a key-value separator (`:`).
+	// E.g.: `<!-- title: frontmatter -->`.
+	Inline bool
+
+	// Title defines the prefix of an optional title line which precedes
+	// header front matters. The title line is included in the data to be
+	// unmarshaled. Up to two lines without the line prefix can follow the
+	// title line (e.g. the AsciiDoc author and revision lines). They are
+	// part of the front matter, but they are not unmarshaled.
+	// E.g.: `= ` (for AsciiDoc documents).
+	Title string
+
+	// Pattern matches the lines of header front matters which do not
+	// have a line prefix. If ToEmptyLine is set, only the first line
+	// of the front matter has to match the pattern.
+	// E.g.: regexp.MustCompile(`^\w+:`).
+	Pattern *regexp.Regexp
+
+	// ToEmptyLine specifies whether a header front matter extends up to
+	// the first empty line, regardless of the content of the lines which
+	// follow the first one (e.g. MultiMarkdown continuation lines).
+	ToEmptyLine bool
+
+	// Scan, if set, is used to locate the end of the front matter, instead
+	// of the end delimiter. In this case, the front matter is detected if
+	// a line starts with the start delimiter, and Scan is called with the
+	// data beginning at that line. It returns the length of the front
+	// matter, which must be followed by the end of the line. If the data is
+	// invalid, the end delimiter is used in order to locate the front
+	// matter and report the decoding error.
+	Scan func(data []byte) (int, error)
 }
 
 // NewFormat returns a new front matter format.
@@ -54,6 +100,170 @@ func newFormat(start, end string, unmarshal UnmarshalFunc,
 	}
 }
 
+// HTMLFormats returns the front matter formats embedded in HTML comments,
+// which are not rendered by most Markdown processors. YAML front matters are
+// identified by opening `<!--` or `<!--yaml` and closing `-->` lines, or by a
+// single `<!-- key: value -->` line. JSON front matters are identified by
+// opening `<!--json` and closing `-->` lines.
+func HTMLFormats() []*Format {
+	formats := []*Format{
+		newFormat("<!--yaml", "-->", yaml.Unmarshal, false, false),
+		newFormat("<!--json", "-->", json.Unmarshal, false, false),
+		newFormat("<!--", "-->", yaml.Unmarshal, false, false),
+	}
+	for _, f := range formats {
+		f.Inline = true
+	}
+
+	return formats
+}
+
+// CommentFormats returns the default front matter formats, embedded in line
+// comments starting with the specified prefix. E.g.: for the `//` prefix,
+// a YAML front matter is identified by opening and closing `// ---` lines.
+func CommentFormats(prefix string) []*Format {
+	formats := defaultFormats()
+	for _, f := range formats {
+		f.Prefix = prefix
+	}
+
+	return formats
+}
+
+// LanguageFormats returns the default front matter formats, embedded in the
+// line comments of the specified programming language (e.g. `go`, `python`,
+// `sql` or `lisp`). If the language is not known, nil is returned.
+func LanguageFormats(lang string) []*Format {
+	prefix, ok := commentPrefixes[strings.ToLower(lang)]
+	if !ok {
+		return nil
+	}
+
+	return CommentFormats(prefix)
+}
+
+var commentPrefixes = map[string]string{
+	// Slash comments.
+	"c":          "//",
+	"c++":        "//",
+	"cpp":        "//",
+	"cs":         "//",
+	"go":         "//",
+	"java":       "//",
+	"javascript": "//",
+	"js":         "//",
+	"kotlin":     "//",
+	"rust":       "//",
+	"swift":      "//",
+	"typescript": "//",
+	"ts":         "//",
+	// Hash comments.
+	"bash":     "#",
+	"makefile": "#",
+	"perl":     "#",
+	"py":       "#",
+	"python":   "#",
+	"r":        "#",
+	"rb":       "#",
+	"ruby":     "#",
+	"sh":       "#",
+	"shell":    "#",
+	"zsh":      "#",
+	// Dash comments.
+	"haskell": "--",
+	"lua":     "--",
+	"sql":     "--",
+	// Semicolon comments.
+	"asm":     ";",
+	"clojure": ";",
+	"ini":     ";",
+	"lisp":    ";",
+	"scheme":  ";",
+}
+
+// isHeader reports whether the format describes a header front matter,
+// which does not have start and end delimiters.
+func (f *Format) isHeader() bool {
+	return f.Start == "" && f.End == ""
+}
+
+// header reports whether the specified line belongs to a header front
+// matter. The title line is only accepted as the first line.
+func (f *Format) header(line []byte, first bool) bool {
+	switch {
+	case f.Prefix != "" && hasPrefix(line, f.Prefix):
+	case f.Pattern != nil && f.Pattern.Match(line):
+	case first:
+		return f.Title != "" && hasPrefix(line, f.Title)
+	default:
+		return f.ToEmptyLine
+	}
+
+	return true
+}
+
+// trim removes the prefix of the format from the specified line, and
+// reports whether the line belongs to the front matter.
+func (f *Format) trim(line []byte) ([]byte, bool) {
+	if f.Prefix == "" {
+		return line, true
+	}
+	if !hasPrefix(line, f.Prefix) {
+		return nil, false
+	}
+
+	return bytes.TrimSpace(line[len(f.Prefix):]), true
+}
+
+// inline returns the data of the single line front matter contained by the
+// specified line, if the format allows it.
+func (f *Format) inline(line []byte) ([]byte, bool) {
+	if !f.Inline || len(line) <= len(f.Start)+len(f.End) ||
+		!hasPrefix(line, f.Start) || !hasSuffix(line, f.End) {
+		return nil, false
+	}
+
+	data := bytes.TrimSpace(line[len(f.Start) : len(line)-len(f.End)])
+	if bytes.IndexByte(data, ':') < 0 {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// strip removes the prefix of the format from every line of the specified
+// front matter data, along with a single space following it.
+func (f *Format) strip(data []byte) []byte {
+	if f.Prefix == "" {
+		return data
+	}
+
+	prefix := []byte(f.Prefix)
+	out := make([]byte, 0, len(data))
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line = data[:i+1]
+		}
+		data = data[len(line):]
+
+		if trimmed := bytes.TrimLeft(line, " \t"); bytes.HasPrefix(trimmed, prefix) {
+			line = trimmed[len(prefix):]
+			if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') {
+				line = line[1:]
+			}
+		}
+		out = append(out, line...)
+	}
+
+	return out
+}
+
+var (
+	defaults     = defaultFormats()
+	htmlDefaults = append(defaultFormats(), HTMLFormats()...)
+)
+
 func defaultFormats() []*Format {
 	return []*Format{
 		// YAML.
@@ -65,6 +275,28 @@ func defaultFormats() []*Format {
 		// JSON.
 		newFormat(";;;", ";;;", json.Unmarshal, false, false),
 		newFormat("---json", "---", json.Unmarshal, false, false),
-		newFormat("{", "}", json.Unmarshal, true, true),
+		newJSONFormat(),
 	}
 }
+
+func newJSONFormat() *Format {
+	f := newFormat("{", "}", json.Unmarshal, true, true)
+	f.Scan = scanJSON
+	return f
+}
+
+// scanJSON returns the length of the JSON object at the beginning of the
+// specified data.
+func scanJSON(data []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return 0, err
+	}
+	if len(raw) == 0 || raw[0] != '{' {
+		return 0, errors.New("not a JSON object")
+	}
+
+	return int(dec.InputOffset()), nil
+}
@@ -0,0 +1,465 @@
+// Package hcl implements decoding for HCL front matters.
+//
+// The decoder supports the HCL native syntax subset which can be expressed
+// as static data: attributes, blocks (with or without labels), strings
+// (including heredocs), numbers, booleans, null, tuples and objects. Blocks
+// are decoded as nested objects, keyed by their type and labels. Multiple
+// blocks with the same type and labels are decoded as lists. Template
+// interpolations (`${...}`) are preserved as literal text, while variables
+// and function calls are not supported.
+//
+// HCL front matters are identified by opening `---hcl` and closing `---`
+// lines.
+package hcl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Formats returns the HCL front matter formats.
+func Formats() []*frontmatter.Format {
+	return []*frontmatter.Format{
+		frontmatter.NewFormat("---hcl", "---", Unmarshal),
+	}
+}
+
+// Unmarshal decodes the HCL encoded `data` and stores the result into the
+// value pointed to by `v`. Keys are mapped to struct fields using the same
+// rules as `frontmatter.Decode`.
+func Unmarshal(data []byte, v interface{}) error {
+	p := &parser{data: string(data), line: 1}
+
+	body, err := p.parseBody(false)
+	if err != nil {
+		return err
+	}
+
+	return frontmatter.Decode(body, v)
+}
+
+type parser struct {
+	data string
+	pos  int
+	line int
+}
+
+func (p *parser) parseBody(nested bool) (map[string]interface{}, error) {
+	body := map[string]interface{}{}
+	for {
+		p.skip(true)
+		if p.eof() {
+			if nested {
+				return nil, p.errorf("unexpected end of input")
+			}
+			return body, nil
+		}
+		if nested && p.peek() == '}' {
+			p.pos++
+			return body, nil
+		}
+
+		name := p.parseIdent()
+		if name == "" {
+			return nil, p.errorf("expected attribute or block, found %q", p.peek())
+		}
+
+		p.skip(false)
+		if !p.eof() && p.peek() == '=' {
+			p.pos++
+			if _, ok := body[name]; ok {
+				return nil, p.errorf("attribute %q redefined", name)
+			}
+
+			val, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			body[name] = val
+
+			if err := p.endOfLine(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Parse block labels.
+		var labels []string
+		for !p.eof() && p.peek() != '{' {
+			var label string
+			var err error
+			if p.peek() == '"' {
+				label, err = p.parseString()
+			} else if label = p.parseIdent(); label == "" {
+				err = p.errorf("expected block label, found %q", p.peek())
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			labels = append(labels, label)
+			p.skip(false)
+		}
+		if p.eof() {
+			return nil, p.errorf("unexpected end of input")
+		}
+		p.pos++
+
+		block, err := p.parseBody(true)
+		if err != nil {
+			return nil, err
+		}
+		if err := addBlock(body, append([]string{name}, labels...), block); err != nil {
+			return nil, p.errorf("%v", err)
+		}
+	}
+}
+
+// addBlock stores the block in the body, nested under its type and labels.
+func addBlock(body map[string]interface{}, keys []string, block map[string]interface{}) error {
+	m := body
+	for _, key := range keys[:len(keys)-1] {
+		switch next := m[key].(type) {
+		case nil:
+			child := map[string]interface{}{}
+			m[key], m = child, child
+		case map[string]interface{}:
+			m = next
+		default:
+			return fmt.Errorf("block %q conflicts with attribute", key)
+		}
+	}
+
+	key := keys[len(keys)-1]
+	switch prev := m[key].(type) {
+	case nil:
+		m[key] = block
+	case map[string]interface{}:
+		m[key] = []interface{}{prev, block}
+	case []interface{}:
+		m[key] = append(prev, block)
+	default:
+		return fmt.Errorf("block %q conflicts with attribute", key)
+	}
+
+	return nil
+}
+
+func (p *parser) parseExpr() (interface{}, error) {
+	p.skip(false)
+	if p.eof() {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch c := p.peek(); {
+	case c == '"':
+		return p.parseString()
+	case c == '[':
+		p.pos++
+		return p.parseTuple()
+	case c == '{':
+		p.pos++
+		return p.parseObject()
+	case strings.HasPrefix(p.data[p.pos:], "<<"):
+		return p.parseHeredoc()
+	case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.parseNumber()
+	}
+
+	switch ident := p.parseIdent(); ident {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	case "":
+		return nil, p.errorf("unexpected character %q", p.peek())
+	default:
+		return nil, p.errorf("unsupported expression %q", ident)
+	}
+}
+
+func (p *parser) parseTuple() ([]interface{}, error) {
+	s := []interface{}{}
+	for {
+		p.skip(true)
+		if p.eof() {
+			return nil, p.errorf("unexpected end of input")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return s, nil
+		}
+
+		val, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, val)
+
+		p.skip(true)
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		} else if !p.eof() && p.peek() != ']' {
+			return nil, p.errorf("expected ',' or ']', found %q", p.peek())
+		}
+	}
+}
+
+func (p *parser) parseObject() (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	for {
+		p.skip(true)
+		if p.eof() {
+			return nil, p.errorf("unexpected end of input")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			return m, nil
+		}
+
+		var key string
+		var err error
+		if p.peek() == '"' {
+			key, err = p.parseString()
+		} else if key = p.parseIdent(); key == "" {
+			err = p.errorf("expected object key, found %q", p.peek())
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p.skip(false)
+		if p.eof() || (p.peek() != '=' && p.peek() != ':') {
+			return nil, p.errorf("expected '=' after key %q", key)
+		}
+		p.pos++
+
+		if m[key], err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+
+		p.skip(false)
+		if !p.eof() && p.peek() == ',' {
+			p.pos++
+		}
+	}
+}
+
+func (p *parser) parseString() (string, error) {
+	p.pos++
+
+	var sb strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.peek()
+		p.pos++
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				return "", p.errorf("unterminated string")
+			}
+
+			c = p.peek()
+			p.pos++
+			switch c {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '"', '\\':
+				sb.WriteByte(c)
+			case 'u', 'U':
+				n := 4
+				if c == 'U' {
+					n = 8
+				}
+				if p.pos+n > len(p.data) {
+					return "", p.errorf("invalid escape sequence")
+				}
+
+				r, err := strconv.ParseUint(p.data[p.pos:p.pos+n], 16, 32)
+				if err != nil {
+					return "", p.errorf("invalid escape sequence")
+				}
+				p.pos += n
+				sb.WriteRune(rune(r))
+			default:
+				return "", p.errorf("invalid escape sequence \\%c", c)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// parseHeredoc parses `<<EOT` and indented `<<-EOT` heredoc strings.
+func (p *parser) parseHeredoc() (string, error) {
+	p.pos += 2
+	indented := !p.eof() && p.peek() == '-'
+	if indented {
+		p.pos++
+	}
+
+	marker := p.parseIdent()
+	if marker == "" {
+		return "", p.errorf("expected heredoc marker")
+	}
+	if err := p.endOfLine(); err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for !p.eof() {
+		end := strings.IndexByte(p.data[p.pos:], '\n')
+		if end < 0 {
+			end = len(p.data) - p.pos
+		}
+		line := strings.TrimRight(p.data[p.pos:p.pos+end], "\r")
+		p.pos += end
+
+		if strings.TrimSpace(line) == marker {
+			if indented {
+				lines = trimIndent(lines)
+			}
+			if len(lines) == 0 {
+				return "", nil
+			}
+			return strings.Join(lines, "\n") + "\n", nil
+		}
+
+		lines = append(lines, line)
+		if !p.eof() {
+			p.pos++
+			p.line++
+		}
+	}
+
+	return "", p.errorf("unterminated heredoc %q", marker)
+}
+
+func trimIndent(lines []string) []string {
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+
+	return lines
+}
+
+func (p *parser) parseNumber() (interface{}, error) {
+	start := p.pos
+	for !p.eof() && strings.IndexByte("0123456789+-.eE", p.peek()) >= 0 {
+		p.pos++
+	}
+
+	token := p.data[start:p.pos]
+	if i, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f, nil
+	}
+
+	return nil, p.errorf("invalid number %q", token)
+}
+
+func (p *parser) parseIdent() string {
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if !(c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' ||
+			(c >= '0' && c <= '9' && p.pos > start) || c >= 0x80) {
+			break
+		}
+		p.pos++
+	}
+
+	return p.data[start:p.pos]
+}
+
+// endOfLine consumes the whitespace and comments up to the end of the line.
+func (p *parser) endOfLine() error {
+	p.skip(false)
+	if p.eof() {
+		return nil
+	}
+
+	switch p.peek() {
+	case '\n':
+		p.pos++
+		p.line++
+		return nil
+	case '}':
+		return nil
+	}
+
+	return p.errorf("unexpected character %q", p.peek())
+}
+
+// skip consumes whitespace and comments. New lines are only consumed
+// if specified.
+func (p *parser) skip(newLines bool) {
+	for !p.eof() {
+		switch rest := p.data[p.pos:]; {
+		case rest[0] == '\n':
+			if !newLines {
+				return
+			}
+			p.line++
+			p.pos++
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r':
+			p.pos++
+		case rest[0] == '#' || strings.HasPrefix(rest, "//"):
+			if i := strings.IndexByte(rest, '\n'); i >= 0 {
+				p.pos += i
+			} else {
+				p.pos = len(p.data)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			i := strings.Index(rest[2:], "*/")
+			if i < 0 {
+				p.pos = len(p.data)
+				return
+			}
+			p.line += strings.Count(rest[:i+4], "\n")
+			p.pos += i + 4
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) peek() byte {
+	return p.data[p.pos]
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("hcl: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
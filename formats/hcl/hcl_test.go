@@ -0,0 +1,129 @@
+package hcl_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/formats/hcl"
+)
+
+type matter struct {
+	Name     string   `json:"name"`
+	Tags     []string `json:"tags"`
+	Metadata struct {
+		Size int `json:"size"`
+	} `json:"metadata"`
+}
+
+func TestFormats(t *testing.T) {
+	inputs := []string{
+		`---hcl
+# HCL front matter.
+name = "frontmatter"
+tags = ["go", "yaml", "json", "toml"]
+
+metadata {
+  size = 10
+}
+---
+rest of the file`,
+		`---hcl
+name = "frontmatter" // Inline comment.
+tags = [
+  "go",
+  "yaml",
+  "json",
+  "toml",
+]
+/* Object expression. */
+metadata = { size: 10 }
+---
+rest of the file`,
+	}
+
+	for _, input := range inputs {
+		var m matter
+		rest, err := frontmatter.MustParse(strings.NewReader(input), &m, hcl.Formats()...)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if m.Name != "frontmatter" || m.Metadata.Size != 10 ||
+			strings.Join(m.Tags, ",") != "go,yaml,json,toml" {
+			t.Fatalf("Input: `%s`\n\nunexpected matter: %+v", input, m)
+		}
+		if string(rest) != "rest of the file" {
+			t.Fatalf("Input: `%s`\n\nunexpected rest: %q", input, rest)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	input := `
+draft   = false
+weight  = -1.5
+summary = <<-EOT
+    first line
+      second line
+    EOT
+empty   = null
+
+author "jane" {
+  email = "jane@example.com"
+}
+author "john" {
+  email = "john@example.com"
+}
+
+link {
+  url = "https://example.com/${path}"
+}
+link {
+  url = "https://example.org"
+}
+`
+
+	var act map[string]interface{}
+	if err := hcl.Unmarshal([]byte(input), &act); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[string]interface{}{
+		"draft":   false,
+		"weight":  -1.5,
+		"summary": "first line\n  second line\n",
+		"empty":   nil,
+		"author": map[string]interface{}{
+			"jane": map[string]interface{}{"email": "jane@example.com"},
+			"john": map[string]interface{}{"email": "john@example.com"},
+		},
+		"link": []interface{}{
+			map[string]interface{}{"url": "https://example.com/${path}"},
+			map[string]interface{}{"url": "https://example.org"},
+		},
+	}
+	if !reflect.DeepEqual(exp, act) {
+		t.Fatalf("Not equal:\nexpected: %#v\nactual  : %#v", exp, act)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	inputs := []string{
+		`name = "frontmatter`,
+		`name = "frontmatter" extra`,
+		`name = var.name`,
+		`name = "a"` + "\n" + `name = "b"`,
+		`block {`,
+		`tags = ["go" "hcl"]`,
+		`text = <<EOT` + "\nunterminated",
+		`= "value"`,
+	}
+
+	for _, input := range inputs {
+		var v interface{}
+		if err := hcl.Unmarshal([]byte(input), &v); err == nil {
+			t.Fatalf("Input: `%s`\n\nexpected error", input)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package ini implements decoding for INI front matters.
+//
+// Sections (`[section]`) are decoded as nested objects. Dotted section names
+// (`[section.subsection]`) define nested sections. Keys and values can be
+// separated by `=` or `:`, and lines starting with `;` or `#` are ignored.
+// Quoted values are unquoted, while the other values are trimmed. Keys
+// suffixed with `[]` (e.g. `tags[] = go`) define lists, which contain the
+// values of all occurrences of the key in the section.
+//
+// INI front matters are identified by opening `---ini` and closing `---`
+// lines.
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Formats returns the INI front matter formats.
+func Formats() []*frontmatter.Format {
+	return []*frontmatter.Format{
+		frontmatter.NewFormat("---ini", "---", Unmarshal),
+	}
+}
+
+// Unmarshal decodes the INI encoded `data` and stores the result into the
+// value pointed to by `v`. Keys are mapped to struct fields using the same
+// rules as `frontmatter.Decode`.
+func Unmarshal(data []byte, v interface{}) error {
+	var (
+		root    = map[string]interface{}{}
+		section = root
+		line    int
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || text[0] == ';' || text[0] == '#' {
+			continue
+		}
+
+		// Parse section.
+		if text[0] == '[' {
+			if text[len(text)-1] != ']' {
+				return fmt.Errorf("ini: line %d: invalid section %q", line, text)
+			}
+
+			var err error
+			if section, err = lookupSection(root, text[1:len(text)-1]); err != nil {
+				return fmt.Errorf("ini: line %d: %v", line, err)
+			}
+			continue
+		}
+
+		// Parse key-value pair.
+		i := strings.IndexAny(text, "=:")
+		if i <= 0 {
+			return fmt.Errorf("ini: line %d: invalid key-value pair %q", line, text)
+		}
+		key, val := strings.TrimSpace(text[:i]), parseValue(text[i+1:])
+
+		if name := strings.TrimSuffix(key, "[]"); name != key {
+			list, _ := section[name].([]interface{})
+			section[name] = append(list, val)
+			continue
+		}
+		if _, ok := section[key]; ok {
+			return fmt.Errorf("ini: line %d: key %q redefined", line, key)
+		}
+		section[key] = val
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return frontmatter.Decode(root, v)
+}
+
+// lookupSection returns the section with the specified dotted name,
+// creating it if necessary.
+func lookupSection(root map[string]interface{}, name string) (map[string]interface{}, error) {
+	section := root
+	for _, key := range strings.Split(name, ".") {
+		if key = strings.TrimSpace(key); key == "" {
+			return nil, fmt.Errorf("invalid section name %q", name)
+		}
+
+		switch next := section[key].(type) {
+		case nil:
+			child := map[string]interface{}{}
+			section[key], section = child, child
+		case map[string]interface{}:
+			section = next
+		default:
+			return nil, fmt.Errorf("section %q conflicts with key", name)
+		}
+	}
+
+	return section, nil
+}
+
+func parseValue(val string) string {
+	val = strings.TrimSpace(val)
+	if len(val) < 2 {
+		return val
+	}
+
+	switch val[0] {
+	case '"':
+		if unquoted, err := strconv.Unquote(val); err == nil {
+			return unquoted
+		}
+	case '\'':
+		if val[len(val)-1] == '\'' {
+			return val[1 : len(val)-1]
+		}
+	}
+
+	return val
+}
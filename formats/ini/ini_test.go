@@ -0,0 +1,107 @@
+package ini_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/formats/ini"
+)
+
+type matter struct {
+	Name     string   `yaml:"name"`
+	Tags     []string `yaml:"tags"`
+	Metadata struct {
+		Size int `yaml:"size"`
+	} `yaml:"metadata"`
+}
+
+func TestFormats(t *testing.T) {
+	inputs := []string{
+		`---ini
+; INI front matter.
+name = frontmatter
+tags[] = go
+tags[] = yaml
+tags[] = json
+tags[] = toml
+
+[metadata]
+size = 10
+---
+rest of the file`,
+		`---ini
+# INI front matter.
+name: "frontmatter"
+tags[]: 'go'
+tags[]: yaml
+tags[]: json
+tags[]: toml
+[ metadata ]
+size: 10
+---
+rest of the file`,
+	}
+
+	for _, input := range inputs {
+		var m matter
+		rest, err := frontmatter.MustParse(strings.NewReader(input), &m, ini.Formats()...)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if m.Name != "frontmatter" || m.Metadata.Size != 10 ||
+			strings.Join(m.Tags, ",") != "go,yaml,json,toml" {
+			t.Fatalf("Input: `%s`\n\nunexpected matter: %+v", input, m)
+		}
+		if string(rest) != "rest of the file" {
+			t.Fatalf("Input: `%s`\n\nunexpected rest: %q", input, rest)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	input := `
+title = "quoted \"value\""
+[author.social]
+github = jdoe
+[author]
+name = John Doe
+`
+
+	var act map[string]interface{}
+	if err := ini.Unmarshal([]byte(input), &act); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[string]interface{}{
+		"title": `quoted "value"`,
+		"author": map[string]interface{}{
+			"name": "John Doe",
+			"social": map[string]interface{}{
+				"github": "jdoe",
+			},
+		},
+	}
+	if !reflect.DeepEqual(exp, act) {
+		t.Fatalf("Not equal:\nexpected: %#v\nactual  : %#v", exp, act)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	inputs := []string{
+		"[section",
+		"[a..b]",
+		"key value",
+		"= value",
+		"key = 1\nkey = 2",
+		"key = 1\n[key]",
+	}
+
+	for _, input := range inputs {
+		var v interface{}
+		if err := ini.Unmarshal([]byte(input), &v); err == nil {
+			t.Fatalf("Input: `%s`\n\nexpected error", input)
+		}
+	}
+}
@@ -0,0 +1,446 @@
+// Package json5 implements decoding for JSON5 and Hjson front matters.
+//
+// The decoder accepts the union of both syntaxes: line and block comments,
+// unquoted and single-quoted keys and strings, trailing or omitted commas,
+// hexadecimal numbers, `Infinity` and `NaN`, quoteless and multiline
+// strings, as well as root objects without braces.
+//
+// JSON5 front matters are identified by opening `---json5` and closing `---`
+// lines, while Hjson front matters are identified by opening `---hjson` and
+// closing `---` lines.
+package json5
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Formats returns the JSON5 and Hjson front matter formats.
+func Formats() []*frontmatter.Format {
+	return []*frontmatter.Format{
+		frontmatter.NewFormat("---json5", "---", Unmarshal),
+		frontmatter.NewFormat("---hjson", "---", Unmarshal),
+	}
+}
+
+// Unmarshal decodes the JSON5 or Hjson encoded `data` and stores the result
+// into the value pointed to by `v`. Keys are mapped to struct fields using
+// the same rules as `frontmatter.Decode`.
+func Unmarshal(data []byte, v interface{}) error {
+	p := &parser{data: string(data), line: 1}
+
+	val, err := p.parseRoot()
+	if err != nil {
+		return err
+	}
+
+	return frontmatter.Decode(val, v)
+}
+
+type parser struct {
+	data string
+	pos  int
+	line int
+}
+
+func (p *parser) parseRoot() (interface{}, error) {
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	if p.eof() {
+		return map[string]interface{}{}, nil
+	}
+
+	var (
+		val interface{}
+		err error
+	)
+	if c := p.peek(); c == '{' || c == '[' {
+		val, err = p.parseValue()
+	} else {
+		// Hjson root objects can omit braces.
+		val, err = p.parseMembers(0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("unexpected character %q", p.peek())
+	}
+
+	return val, nil
+}
+
+func (p *parser) parseValue() (interface{}, error) {
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	if p.eof() {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch c := p.peek(); c {
+	case '{':
+		p.pos++
+		return p.parseMembers('}')
+	case '[':
+		p.pos++
+		return p.parseElements()
+	case '"', '\'':
+		if strings.HasPrefix(p.data[p.pos:], "'''") {
+			return p.parseMultiline()
+		}
+		return p.parseString()
+	case ',', ':', '}', ']':
+		return nil, p.errorf("unexpected character %q", c)
+	}
+
+	return p.parseLiteral()
+}
+
+func (p *parser) parseMembers(end byte) (map[string]interface{}, error) {
+	m := map[string]interface{}{}
+	for {
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.eof() {
+			if end == 0 {
+				return m, nil
+			}
+			return nil, p.errorf("unexpected end of input")
+		}
+		if p.peek() == end {
+			p.pos++
+			return m, nil
+		}
+
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.eof() || p.peek() != ':' {
+			return nil, p.errorf("expected ':' after key %q", key)
+		}
+		p.pos++
+
+		if m[key], err = p.parseValue(); err != nil {
+			return nil, err
+		}
+		if err := p.separator(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (p *parser) parseElements() ([]interface{}, error) {
+	s := []interface{}{}
+	for {
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.eof() {
+			return nil, p.errorf("unexpected end of input")
+		}
+		if p.peek() == ']' {
+			p.pos++
+			return s, nil
+		}
+
+		val, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, val)
+
+		if err := p.separator(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// separator consumes an optional comma following an object member or an
+// array element.
+func (p *parser) separator() error {
+	if err := p.skip(); err != nil {
+		return err
+	}
+	if !p.eof() && p.peek() == ',' {
+		p.pos++
+	}
+
+	return nil
+}
+
+func (p *parser) parseKey() (string, error) {
+	if c := p.peek(); c == '"' || c == '\'' {
+		return p.parseString()
+	}
+
+	start := p.pos
+	for !p.eof() {
+		c := p.peek()
+		if c == ':' || c == ',' || c == '{' || c == '}' || c == '[' ||
+			c == ']' || c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return "", p.errorf("expected key, found %q", p.peek())
+	}
+
+	return p.data[start:p.pos], nil
+}
+
+func (p *parser) parseString() (string, error) {
+	quote := p.data[p.pos]
+	p.pos++
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.data[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			return sb.String(), nil
+		case c == '\n':
+			return "", p.errorf("unterminated string")
+		case c != '\\':
+			sb.WriteByte(c)
+			p.pos++
+			continue
+		}
+
+		// Escape sequences.
+		p.pos++
+		if p.eof() {
+			return "", p.errorf("unterminated string")
+		}
+
+		c = p.data[p.pos]
+		p.pos++
+		switch c {
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'v':
+			sb.WriteByte('\v')
+		case '0':
+			sb.WriteByte(0)
+		case '\r':
+			if !p.eof() && p.data[p.pos] == '\n' {
+				p.pos++
+			}
+			p.line++
+		case '\n':
+			// Line continuation.
+			p.line++
+		case 'x', 'u':
+			n := 2
+			if c == 'u' {
+				n = 4
+			}
+			if p.pos+n > len(p.data) {
+				return "", p.errorf("invalid escape sequence")
+			}
+
+			r, err := strconv.ParseUint(p.data[p.pos:p.pos+n], 16, 32)
+			if err != nil {
+				return "", p.errorf("invalid escape sequence")
+			}
+			p.pos += n
+			sb.WriteRune(rune(r))
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// parseMultiline parses Hjson multiline strings, enclosed by triple single quotes.
+// The indentation of the opening quotes is removed from each line.
+func (p *parser) parseMultiline() (string, error) {
+	indent := p.pos - (strings.LastIndexByte(p.data[:p.pos], '\n') + 1)
+	p.pos += 3
+
+	end := strings.Index(p.data[p.pos:], "'''")
+	if end < 0 {
+		return "", p.errorf("unterminated multiline string")
+	}
+	raw := p.data[p.pos : p.pos+end]
+	p.line += strings.Count(raw, "\n")
+	p.pos += end + 3
+
+	// Ignore the first line, if empty, and the indentation of the last line.
+	if i := strings.IndexByte(raw, '\n'); i >= 0 && strings.TrimSpace(raw[:i]) == "" {
+		raw = raw[i+1:]
+	}
+	if i := strings.LastIndexByte(raw, '\n'); i >= 0 && strings.TrimSpace(raw[i:]) == "" {
+		raw = raw[:i]
+	}
+
+	lines := strings.Split(raw, "\n")
+	for i, line := range lines {
+		n := 0
+		for n < len(line) && n < indent && (line[n] == ' ' || line[n] == '\t') {
+			n++
+		}
+		lines[i] = line[n:]
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// parseLiteral parses numbers, literals and Hjson quoteless strings.
+// Quoteless strings extend up to the end of the line.
+func (p *parser) parseLiteral() (interface{}, error) {
+	start := p.pos
+	for !p.eof() && !strings.ContainsRune(",:[]{}\"' \t\r\n", rune(p.peek())) {
+		if strings.HasPrefix(p.data[p.pos:], "//") || strings.HasPrefix(p.data[p.pos:], "/*") {
+			break
+		}
+		p.pos++
+	}
+
+	if token := p.data[start:p.pos]; token != "" && p.endOfValue() {
+		if val, ok := parseToken(token); ok {
+			return val, nil
+		}
+	}
+
+	// Quoteless string.
+	end := strings.IndexByte(p.data[start:], '\n')
+	if end < 0 {
+		end = len(p.data) - start
+	}
+	p.pos = start + end
+
+	return strings.TrimSpace(p.data[start:p.pos]), nil
+}
+
+// endOfValue reports whether only separators, comments or whitespace
+// follow on the current line.
+func (p *parser) endOfValue() bool {
+	rest := p.data[p.pos:]
+	if i := strings.IndexByte(rest, '\n'); i >= 0 {
+		rest = rest[:i]
+	}
+	rest = strings.TrimSpace(rest)
+
+	return rest == "" || strings.ContainsRune(",]}#", rune(rest[0])) ||
+		strings.HasPrefix(rest, "//") || strings.HasPrefix(rest, "/*")
+}
+
+func parseToken(token string) (interface{}, bool) {
+	switch token {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+
+	sign, num := 1.0, token
+	switch num[0] {
+	case '+':
+		num = num[1:]
+	case '-':
+		sign, num = -1, num[1:]
+	}
+
+	switch {
+	case num == "Infinity":
+		return math.Inf(int(sign)), true
+	case num == "NaN":
+		return math.NaN(), true
+	case strings.HasPrefix(num, "0x") || strings.HasPrefix(num, "0X"):
+		u, err := strconv.ParseUint(num[2:], 16, 63)
+		if err != nil {
+			return nil, false
+		}
+		return int64(sign) * int64(u), true
+	}
+	if num == "" || !(num[0] >= '0' && num[0] <= '9' || num[0] == '.') {
+		return nil, false
+	}
+
+	if i, err := strconv.ParseInt(token, 10, 64); err == nil {
+		return i, true
+	}
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f, true
+	}
+
+	return nil, false
+}
+
+// skip consumes whitespace and comments.
+func (p *parser) skip() error {
+	for !p.eof() {
+		switch rest := p.data[p.pos:]; {
+		case rest[0] == '\n':
+			p.line++
+			p.pos++
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r':
+			p.pos++
+		case rest[0] == '#' || strings.HasPrefix(rest, "//"):
+			if i := strings.IndexByte(rest, '\n'); i >= 0 {
+				p.pos += i
+			} else {
+				p.pos = len(p.data)
+			}
+		case strings.HasPrefix(rest, "/*"):
+			i := strings.Index(rest[2:], "*/")
+			if i < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.line += strings.Count(rest[:i+4], "\n")
+			p.pos += i + 4
+		default:
+			r, size := utf8.DecodeRuneInString(rest)
+			if size == 0 || !unicode.IsSpace(r) {
+				return nil
+			}
+			p.pos += size
+		}
+	}
+
+	return nil
+}
+
+func (p *parser) peek() byte {
+	return p.data[p.pos]
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("json5: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
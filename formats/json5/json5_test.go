@@ -0,0 +1,135 @@
+package json5_test
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/formats/json5"
+)
+
+type matter struct {
+	Name     string   `json:"name"`
+	Tags     []string `json:"tags"`
+	Metadata struct {
+		Size int `json:"size"`
+	} `json:"metadata"`
+}
+
+func TestFormats(t *testing.T) {
+	inputs := []string{
+		`---json5
+// JSON5 front matter.
+{
+  name: 'frontmatter',
+  tags: ["go", 'yaml', "json", "toml",],
+  /* Nested object. */
+  metadata: {size: 0xA},
+}
+---
+rest of the file`,
+		`---hjson
+# Hjson front matter.
+{
+  name: frontmatter
+  tags: [
+    go
+    yaml
+    json
+    toml
+  ]
+  metadata: {
+    size: 10
+  }
+}
+---
+rest of the file`,
+		`---hjson
+name: frontmatter
+tags: ["go", "yaml", "json", "toml"]
+metadata: {size: +10}
+---
+rest of the file`,
+	}
+
+	for _, input := range inputs {
+		var m matter
+		rest, err := frontmatter.MustParse(strings.NewReader(input), &m, json5.Formats()...)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if m.Name != "frontmatter" || m.Metadata.Size != 10 ||
+			strings.Join(m.Tags, ",") != "go,yaml,json,toml" {
+			t.Fatalf("Input: `%s`\n\nunexpected matter: %+v", input, m)
+		}
+		if string(rest) != "rest of the file" {
+			t.Fatalf("Input: `%s`\n\nunexpected rest: %q", input, rest)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	input := `{
+  // Strings.
+  single: 'it\'s',
+  escaped: "a\tb\u0041",
+  quoteless: it's a string, really
+  multiline:
+    '''
+    first line
+      second line
+    '''
+  // Numbers.
+  int: -5,
+  float: .5,
+  exp: 1e3,
+  inf: -Infinity,
+  "quoted key": null,
+  list: [true, false,],
+}`
+
+	var act map[string]interface{}
+	if err := json5.Unmarshal([]byte(input), &act); err != nil {
+		t.Fatal(err)
+	}
+	if inf, ok := act["inf"].(float64); !ok || !math.IsInf(inf, -1) {
+		t.Fatalf("expected negative infinity, got %v", act["inf"])
+	}
+	delete(act, "inf")
+
+	exp := map[string]interface{}{
+		"single":     "it's",
+		"escaped":    "a\tbA",
+		"quoteless":  "it's a string, really",
+		"multiline":  "first line\n  second line",
+		"int":        int64(-5),
+		"float":      0.5,
+		"exp":        1000.0,
+		"quoted key": nil,
+		"list":       []interface{}{true, false},
+	}
+	if !reflect.DeepEqual(exp, act) {
+		t.Fatalf("Not equal:\nexpected: %#v\nactual  : %#v", exp, act)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	inputs := []string{
+		`{name: "frontmatter"`,
+		`{name "frontmatter"}`,
+		`{name: "frontmatter}`,
+		`[1, 2`,
+		`{a: 1} }`,
+		`/* comment`,
+		`{a: "\u00"}`,
+	}
+
+	for _, input := range inputs {
+		var v interface{}
+		if err := json5.Unmarshal([]byte(input), &v); err == nil {
+			t.Fatalf("Input: `%s`\n\nexpected error", input)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+// Package properties implements decoding for Java properties front matters.
+//
+// Keys and values can be separated by `=`, `:` or whitespace, and lines
+// starting with `#` or `!` are ignored. Lines ending with an odd number of
+// backslashes are continued on the next line, and the standard escape
+// sequences (including `\uXXXX`) are supported. Dotted keys (e.g.
+// `metadata.size`) define nested objects, while indexed keys (e.g.
+// `tags[0]`) define lists. List elements keep their indexes, and missing
+// elements are decoded as `nil` (e.g. `tags[0]` and `tags[2]` define a
+// list of three elements, the second one being `nil`).
+//
+// Properties front matters are identified by opening `---properties` and
+// closing `---` lines.
+package properties
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Formats returns the Java properties front matter formats.
+func Formats() []*frontmatter.Format {
+	return []*frontmatter.Format{
+		frontmatter.NewFormat("---properties", "---", Unmarshal),
+	}
+}
+
+// Unmarshal decodes the Java properties encoded `data` and stores the result
+// into the value pointed to by `v`. Keys are mapped to struct fields using
+// the same rules as `frontmatter.Decode`.
+func Unmarshal(data []byte, v interface{}) error {
+	props, err := parse(data)
+	if err != nil {
+		return err
+	}
+
+	root := map[string]interface{}{}
+	for _, prop := range props {
+		if err := set(root, prop.key, prop.val); err != nil {
+			return fmt.Errorf("properties: line %d: %v", prop.line, err)
+		}
+	}
+
+	return frontmatter.Decode(lists(root), v)
+}
+
+type property struct {
+	key  string
+	val  string
+	line int
+}
+
+func parse(data []byte) ([]property, error) {
+	var (
+		props   []property
+		logical strings.Builder
+		line    int
+		start   int
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimLeft(scanner.Text(), " \t\f")
+		if logical.Len() == 0 {
+			if text == "" || text[0] == '#' || text[0] == '!' {
+				continue
+			}
+			start = line
+		}
+
+		// Handle line continuations.
+		if n := len(text) - len(strings.TrimRight(text, "\\")); n%2 == 1 {
+			logical.WriteString(text[:len(text)-1])
+			continue
+		}
+		logical.WriteString(text)
+
+		key, val, err := split(logical.String())
+		if err != nil {
+			return nil, fmt.Errorf("properties: line %d: %v", start, err)
+		}
+		props = append(props, property{key: key, val: val, line: start})
+		logical.Reset()
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if logical.Len() > 0 {
+		key, val, err := split(logical.String())
+		if err != nil {
+			return nil, fmt.Errorf("properties: line %d: %v", start, err)
+		}
+		props = append(props, property{key: key, val: val, line: start})
+	}
+
+	return props, nil
+}
+
+// split separates the key and the value of the specified logical line.
+func split(text string) (string, string, error) {
+	end := len(text)
+	for i := 0; i < len(text); i++ {
+		if c := text[i]; c == '\\' {
+			i++
+		} else if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			end = i
+			break
+		}
+	}
+
+	rest := strings.TrimLeft(text[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	key, err := unescape(text[:end])
+	if err != nil {
+		return "", "", err
+	}
+	val, err := unescape(rest)
+	if err != nil {
+		return "", "", err
+	}
+
+	return key, val, nil
+}
+
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, "\\") {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch c := s[i]; c {
+		case 'n':
+			sb.WriteByte('\n')
+		case 'r':
+			sb.WriteByte('\r')
+		case 't':
+			sb.WriteByte('\t')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("invalid escape sequence in %q", s)
+			}
+
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid escape sequence in %q", s)
+			}
+			sb.WriteRune(rune(r))
+			i += 4
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// maxIndex is the maximum index of list elements, which limits the size of
+// the lists allocated for sparse indexes.
+const maxIndex = 1<<16 - 1
+
+// set stores the value in the tree, nested under the dotted key.
+// Indexed key segments (e.g. `tags[0]`) are stored as maps with integer
+// keys, which are converted to lists after all properties are set.
+func set(root map[string]interface{}, key, val string) error {
+	var segments []string
+	for _, segment := range strings.Split(key, ".") {
+		name := segment
+		var indexes []string
+		if i := strings.IndexByte(segment, '['); i > 0 && strings.HasSuffix(segment, "]") {
+			name = segment[:i]
+			indexes = strings.Split(segment[i+1:len(segment)-1], "][")
+		}
+		if name == "" {
+			return fmt.Errorf("invalid key %q", key)
+		}
+
+		segments = append(segments, name)
+		for _, index := range indexes {
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i > maxIndex {
+				return fmt.Errorf("invalid key %q", key)
+			}
+			segments = append(segments, "\x00"+strconv.Itoa(i))
+		}
+	}
+
+	m := root
+	for _, segment := range segments[:len(segments)-1] {
+		switch next := m[segment].(type) {
+		case nil:
+			child := map[string]interface{}{}
+			m[segment], m = child, child
+		case map[string]interface{}:
+			m = next
+		default:
+			return fmt.Errorf("key %q conflicts with a previous key", key)
+		}
+	}
+
+	last := segments[len(segments)-1]
+	if _, ok := m[last]; ok {
+		return fmt.Errorf("key %q redefined", key)
+	}
+	m[last] = val
+
+	return nil
+}
+
+// lists converts the maps having index keys into lists, placing each value
+// at its index.
+func lists(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	indexed := len(m) > 0
+	for key, val := range m {
+		m[key] = lists(val)
+		if !strings.HasPrefix(key, "\x00") {
+			indexed = false
+		}
+	}
+	if !indexed {
+		for key := range m {
+			if strings.HasPrefix(key, "\x00") {
+				m[key[1:]] = m[key]
+				delete(m, key)
+			}
+		}
+		return m
+	}
+
+	size := 0
+	for key := range m {
+		if i, _ := strconv.Atoi(key[1:]); i >= size {
+			size = i + 1
+		}
+	}
+
+	s := make([]interface{}, size)
+	for key, val := range m {
+		i, _ := strconv.Atoi(key[1:])
+		s[i] = val
+	}
+	return s
+}
@@ -0,0 +1,110 @@
+package properties_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/formats/properties"
+)
+
+type matter struct {
+	Name     string   `yaml:"name"`
+	Tags     []string `yaml:"tags"`
+	Metadata struct {
+		Size int `yaml:"size"`
+	} `yaml:"metadata"`
+}
+
+func TestFormats(t *testing.T) {
+	inputs := []string{
+		`---properties
+# Properties front matter.
+name = frontmatter
+tags[0] = go
+tags[1] = yaml
+tags[2] = json
+tags[3] = toml
+metadata.size = 10
+---
+rest of the file`,
+		`---properties
+! Properties front matter.
+name:front\
+     matter
+tags[3] toml
+tags[2] json
+tags[1] yaml
+tags[0] go
+metadata.size: 10
+---
+rest of the file`,
+	}
+
+	for _, input := range inputs {
+		var m matter
+		rest, err := frontmatter.MustParse(strings.NewReader(input), &m, properties.Formats()...)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if m.Name != "frontmatter" || m.Metadata.Size != 10 ||
+			strings.Join(m.Tags, ",") != "go,yaml,json,toml" {
+			t.Fatalf("Input: `%s`\n\nunexpected matter: %+v", input, m)
+		}
+		if string(rest) != "rest of the file" {
+			t.Fatalf("Input: `%s`\n\nunexpected rest: %q", input, rest)
+		}
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	input := `
+title = line\tone\nline two \u0041
+key\ with\ spaces = value
+empty
+links[0].url = https://example.com
+links[1].url = https://example.org
+sparse[0] = a
+sparse[3] = b
+`
+
+	var act map[string]interface{}
+	if err := properties.Unmarshal([]byte(input), &act); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[string]interface{}{
+		"title":           "line\tone\nline two A",
+		"key with spaces": "value",
+		"empty":           "",
+		"links": []interface{}{
+			map[string]interface{}{"url": "https://example.com"},
+			map[string]interface{}{"url": "https://example.org"},
+		},
+		"sparse": []interface{}{"a", nil, nil, "b"},
+	}
+	if !reflect.DeepEqual(exp, act) {
+		t.Fatalf("Not equal:\nexpected: %#v\nactual  : %#v", exp, act)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	inputs := []string{
+		"key = 1\nkey = 2",
+		"key = 1\nkey.nested = 2",
+		"tags[a] = 1",
+		"tags[-1] = 1",
+		"tags[65536] = 1",
+		"tags[1] = 1\ntags[01] = 2",
+		".key = 1",
+		`key = \u00`,
+	}
+
+	for _, input := range inputs {
+		var v interface{}
+		if err := properties.Unmarshal([]byte(input), &v); err == nil {
+			t.Fatalf("Input: `%s`\n\nexpected error", input)
+		}
+	}
+}
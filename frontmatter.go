@@ -17,24 +17,104 @@ front matter formats.
 
 If the default formats are not suitable for your use case, you can easily bring
 your own. See the examples for more information.
+
+Front matters embedded in source code comments (e.g. `// ---` or `# +++`) can
+be detected using the formats returned by `CommentFormats` and `LanguageFormats`.
+Front matters embedded in HTML comments (e.g. `<!--` and `-->`) can be detected
+using the formats returned by `HTMLFormats`. Org mode keyword lines and AsciiDoc
+header attributes can be detected using `OrgFormat` and `AsciiDocFormat`, while
+MultiMarkdown metadata can be detected using `MultiMarkdownFormat`.
+
+Additional data languages (JSON5 and Hjson, HCL, INI and Java properties) are
+supported by the subpackages of the `formats` directory, in order to keep the
+core package dependency-light.
 */
 package frontmatter
 
 import (
 	"errors"
 	"io"
+	"reflect"
+	"unsafe"
 )
 
 // ErrNotFound is reported by `MustParse` when a front matter is not found.
 var ErrNotFound = errors.New("not found")
 
+// Options holds the configuration used to detect and decode front matters.
+type Options struct {
+	// Formats defines the front matter formats to detect.
+	// If no formats are provided, the default formats are used.
+	Formats []*Format
+
+	// Preamble describes the content allowed to precede the front matter.
+	// If nil, only empty lines can precede the front matter.
+	Preamble *Preamble
+
+	// HTMLComments specifies whether the formats returned by `HTMLFormats`
+	// are detected in addition to the default formats. It has no effect
+	// if custom formats are provided.
+	HTMLComments bool
+
+	// Tail specifies whether the front matter is located at the end of
+	// the data, instead of the beginning. The front matter must be the
+	// last block of the data, optionally followed by empty lines, and
+	// the data preceding it is returned as the body. Header formats and
+	// preambles are not supported in this mode.
+	Tail bool
+
+	// DecodeHooks defines the hooks used to convert the decoded front
+	// matter values. If provided, the front matter is unmarshaled into
+	// generic data, which is then stored into the target value using
+	// `DecodeWithHooks`. This allows the same conversion rules to be
+	// applied regardless of the format.
+	// E.g.: []DecodeHook{StringToDurationHook(), StringToSliceHook(",")}.
+	DecodeHooks []DecodeHook
+
+	// Includes defines the configuration used to compose the front matter
+	// from shared files. The includes are resolved before interpolation.
+	// If nil, the front matter cannot include other files.
+	Includes *Includes
+
+	// Interpolation defines the configuration used to replace the
+	// placeholders contained by the front matter values, such as
+	// environment variables or references to other keys. If nil, the
+	// values are not interpolated.
+	Interpolation *Interpolation
+}
+
+// Document contains the result of parsing a front matter.
+type Document struct {
+	// Format is the detected front matter format.
+	// It is nil if a front matter was not found. If the default formats
+	// are used, it is a copy of the detected default format, which can be
+	// modified without affecting other parses.
+	Format *Format
+
+	// Preamble contains the data preceding the front matter, including
+	// empty lines. It can be used to restore the original content.
+	Preamble []byte
+
+	// Matter contains the raw front matter, including its delimiters.
+	Matter []byte
+
+	// Body contains the data following the front matter. If a front
+	// matter was not found, it contains the original data.
+	Body []byte
+}
+
 // Parse decodes the front matter from the specified reader into the value
 // pointed to by `v`, and returns the rest of the data. If a front matter
 // is not present, the original data is returned and `v` is left unchanged.
 // Front matters are detected and decoded based on the passed in `formats`.
 // If no formats are provided, the default formats are used.
 func Parse(r io.Reader, v interface{}, formats ...*Format) ([]byte, error) {
-	return newParser(r).parse(v, formats, false)
+	doc, err := newParser(r).parse(v, &Options{Formats: formats}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Body, nil
 }
 
 // MustParse decodes the front matter from the specified reader into the
@@ -43,5 +123,102 @@ func Parse(r io.Reader, v interface{}, formats ...*Format) ([]byte, error) {
 // Front matters are detected and decoded based on the passed in `formats`.
 // If no formats are provided, the default formats are used.
 func MustParse(r io.Reader, v interface{}, formats ...*Format) ([]byte, error) {
-	return newParser(r).parse(v, formats, true)
+	doc, err := newParser(r).parse(v, &Options{Formats: formats}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Body, nil
+}
+
+// ParseBytes decodes the front matter from the specified data into the value
+// pointed to by `v`, and returns the rest of the data. The data is scanned in
+// place and the returned body references the original data, without copying
+// it. If a front matter is not present, the original data is returned and `v`
+// is left unchanged.
+// Front matters are detected and decoded based on the passed in `formats`.
+// If no formats are provided, the default formats are used.
+func ParseBytes(data []byte, v interface{}, formats ...*Format) ([]byte, error) {
+	doc, err := newBytesParser(data).parse(v, &Options{Formats: formats}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Body, nil
+}
+
+// MustParseBytes decodes the front matter from the specified data into the
+// value pointed to by `v`, and returns the rest of the data, without copying
+// it. If a front matter is not present, `ErrNotFound` is reported.
+// Front matters are detected and decoded based on the passed in `formats`.
+// If no formats are provided, the default formats are used.
+func MustParseBytes(data []byte, v interface{}, formats ...*Format) ([]byte, error) {
+	doc, err := newBytesParser(data).parse(v, &Options{Formats: formats}, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc.Body, nil
+}
+
+// ParseString decodes the front matter from the specified string into the
+// value pointed to by `v`, and returns the rest of the string. The string is
+// scanned in place and the returned body is a substring of the original one.
+// Since the unmarshal functions of the formats receive the front matter data
+// without it being copied, they must not modify it.
+// If a front matter is not present, the original string is returned and `v`
+// is left unchanged.
+// Front matters are detected and decoded based on the passed in `formats`.
+// If no formats are provided, the default formats are used.
+func ParseString(s string, v interface{}, formats ...*Format) (string, error) {
+	body, err := ParseBytes(stringBytes(s), v, formats...)
+	if err != nil {
+		return "", err
+	}
+
+	return s[len(s)-len(body):], nil
+}
+
+// MustParseString decodes the front matter from the specified string into
+// the value pointed to by `v`, and returns the rest of the string, without
+// copying it. If a front matter is not present, `ErrNotFound` is reported.
+// Front matters are detected and decoded based on the passed in `formats`.
+// If no formats are provided, the default formats are used.
+func MustParseString(s string, v interface{}, formats ...*Format) (string, error) {
+	body, err := MustParseBytes(stringBytes(s), v, formats...)
+	if err != nil {
+		return "", err
+	}
+
+	return s[len(s)-len(body):], nil
+}
+
+// stringBytes returns the bytes of the specified string, without copying
+// them. The returned slice must not be modified.
+func stringBytes(s string) []byte {
+	var b []byte
+	sh := (*reflect.StringHeader)(unsafe.Pointer(&s))
+	bh := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	bh.Data, bh.Len, bh.Cap = sh.Data, sh.Len, sh.Len
+
+	return b
+}
+
+// ParseDocument decodes the front matter from the specified reader into the
+// value pointed to by `v`, and returns the parsed document. If a front matter
+// is not present, the returned document contains the original data as its
+// body and `v` is left unchanged.
+// Front matters are detected and decoded based on the passed in options.
+// If no options are provided, the default options are used.
+func ParseDocument(r io.Reader, v interface{}, opts *Options) (*Document, error) {
+	return newParser(r).parse(v, opts, false)
+}
+
+// MustParseDocument decodes the front matter from the specified reader into
+// the value pointed to by `v`, and returns the parsed document. If a front
+// matter is not present, `ErrNotFound` is reported.
+// Front matters are detected and decoded based on the passed in options.
+// If no options are provided, the default options are used.
+func MustParseDocument(r io.Reader, v interface{}, opts *Options) (*Document, error) {
+	return newParser(r).parse(v, opts, true)
 }
@@ -2,6 +2,7 @@ package frontmatter_test
 
 import (
 	"io"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -199,6 +200,44 @@ rest of the file`,
 			expMustParse: expValidMatter,
 		},
 
+		{
+			input: `{"name": "frontmatter", "tags": ["go", "yaml", "json", "toml"], "metadata": {"size": 10}}
+
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+		},
+
+		{
+			input: `
+{"name": "frontmatter",
+ "tags": ["go", "yaml", "json", "toml"],
+ "metadata": {"size": 10}}
+
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+		},
+
+		{
+			input: `{
+  "name": "frontmatter",
+  "tags": ["go", "yaml", "json", "toml"],
+  "metadata": {
+	"size": 10
+  }}	 
+  
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+		},
+
+		{
+			input:        `{"name": "frontmatter", "tags": ["go", "yaml", "json", "toml"], "metadata": {"size": 10}}`,
+			expParse:     expNoRest,
+			expMustParse: expNoRest,
+		},
+
 		{
 			input: `
 ---
@@ -601,6 +640,35 @@ rest of the file`,
 			expMustParse: expMatterErr,
 		},
 
+		{
+			input: `
+{"name": "frontmatter"} trailing data
+
+rest of the file`,
+			expParse:     expNoMatter,
+			expMustParse: expMatterErr,
+		},
+
+		{
+			input: `
+{"name": "frontmatter"}
+rest of the file`,
+			expParse:     expNoMatter,
+			expMustParse: expMatterErr,
+		},
+
+		{
+			input:        "{{< figure src=\"a.png\" >}}\n\nSome text\n\n```go\nfunc main() {\n}\n\n```\n",
+			expParse:     expNoMatter,
+			expMustParse: expMatterErr,
+		},
+
+		{
+			input:        "{\"a\":1}\n}",
+			expParse:     expNoMatter,
+			expMustParse: expMatterErr,
+		},
+
 		// -----------------
 		// - Invalid data. -
 		// -----------------
@@ -702,6 +770,153 @@ rest of the file`,
 				frontmatter.NewFormat("...", "...", yaml.Unmarshal),
 			},
 		},
+
+		// ----------------
+		// - HTML formats -
+		// ----------------
+
+		{
+			input: `
+<!--
+name: "frontmatter"
+tags: ["go", "yaml", "json", "toml"]
+metadata:
+  size: 10
+-->
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.HTMLFormats(),
+		},
+
+		{
+			input: `
+<!--yaml
+name: "frontmatter"
+tags: ["go", "yaml", "json", "toml"]
+metadata:
+  size: 10
+-->
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.HTMLFormats(),
+		},
+
+		{
+			input: `
+<!--json
+{
+  "name": "frontmatter",
+  "tags": ["go", "yaml", "json", "toml"],
+  "metadata": {"size": 10}
+}
+-->
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.HTMLFormats(),
+		},
+
+		{
+			input: `<!-- {name: "frontmatter", tags: ["go", "yaml", "json", "toml"], metadata: {size: 10}} -->
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.HTMLFormats(),
+		},
+
+		{
+			input: `<!-- prettier-ignore -->
+rest of the file`,
+			expParse:     expNoMatter,
+			expMustParse: expMatterErr,
+			formats:      frontmatter.HTMLFormats(),
+		},
+
+		// -------------------
+		// - Comment formats -
+		// -------------------
+
+		{
+			input: `
+// ---
+// name: "frontmatter"
+// tags:
+//   - "go"
+//   - "yaml"
+//   - "json"
+//   - "toml"
+// metadata:
+//   size: 10
+// ---
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.LanguageFormats("go"),
+		},
+
+		{
+			input: `
+# +++
+# name = "frontmatter"
+# tags = ["go", "yaml", "json", "toml"]
+# [metadata]
+# size = 10
+# +++
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.LanguageFormats("python"),
+		},
+
+		{
+			input: `
+-- {
+--   "name": "frontmatter",
+--   "tags": ["go", "yaml", "json", "toml"],
+--   "metadata": {"size": 10}
+-- }
+
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.LanguageFormats("sql"),
+		},
+
+		{
+			input: `
+; ;;;
+; {"name": "frontmatter", "tags": ["go", "yaml", "json", "toml"],
+;  "metadata": {"size": 10}}
+; ;;;
+rest of the file`,
+			expParse:     expValidMatter,
+			expMustParse: expValidMatter,
+			formats:      frontmatter.CommentFormats(";"),
+		},
+
+		{
+			input: `
+// ---
+// name: "frontmatter"
+rest of the file
+// ---`,
+			expParse:     expNoMatter,
+			expMustParse: expMatterErr,
+			formats:      frontmatter.LanguageFormats("go"),
+		},
+
+		{
+			input: `
+---
+name: "frontmatter"
+---
+rest of the file`,
+			expParse:     expNoMatter,
+			expMustParse: expMatterErr,
+			formats:      frontmatter.LanguageFormats("go"),
+		},
 	}
 
 	failFunc := func(in string, exp, act interface{}) {
@@ -740,8 +955,481 @@ rest of the file`,
 		checkFunc(in, mExp, mAct, rExp, string(rest), hasErr, err != nil)
 	}
 
+	bytesFunc := func(parse func([]byte, interface{},
+		...*frontmatter.Format) ([]byte, error)) parseFunc {
+		return func(r io.Reader, v interface{},
+			formats ...*frontmatter.Format) ([]byte, error) {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+			return parse(data, v, formats...)
+		}
+	}
+	stringFunc := func(parse func(string, interface{},
+		...*frontmatter.Format) (string, error)) parseFunc {
+		return func(r io.Reader, v interface{},
+			formats ...*frontmatter.Format) ([]byte, error) {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return nil, err
+			}
+
+			rest, err := parse(string(data), v, formats...)
+			if err != nil {
+				return nil, err
+			}
+			return []byte(rest), nil
+		}
+	}
+
 	for _, tc := range testCases {
 		testFunc(tc.input, tc.formats, tc.expParse, frontmatter.Parse)
 		testFunc(tc.input, tc.formats, tc.expMustParse, frontmatter.MustParse)
+		testFunc(tc.input, tc.formats, tc.expParse, bytesFunc(frontmatter.ParseBytes))
+		testFunc(tc.input, tc.formats, tc.expMustParse, bytesFunc(frontmatter.MustParseBytes))
+		testFunc(tc.input, tc.formats, tc.expParse, stringFunc(frontmatter.ParseString))
+		testFunc(tc.input, tc.formats, tc.expMustParse, stringFunc(frontmatter.MustParseString))
+	}
+}
+
+func TestParseBytesNoCopy(t *testing.T) {
+	var m struct {
+		Name string `yaml:"name"`
+	}
+
+	data := []byte("---\nname: frontmatter\n---\nrest of the file")
+	rest, err := frontmatter.ParseBytes(data, &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rest) != "rest of the file" {
+		t.Fatalf("expected rest %q, got %q", "rest of the file", rest)
+	}
+	if &rest[0] != &data[len(data)-len(rest)] {
+		t.Fatal("rest does not reference the original data")
+	}
+
+	data = []byte("rest of the file")
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := frontmatter.ParseBytes(data, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 3 {
+		t.Fatalf("expected at most 3 allocations, got %v", allocs)
+	}
+
+	allocs = testing.AllocsPerRun(100, func() {
+		if _, err := frontmatter.ParseString("rest of the file", nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs > 3 {
+		t.Fatalf("expected at most 3 allocations, got %v", allocs)
+	}
+}
+
+func TestHTMLComments(t *testing.T) {
+	var m struct {
+		Title string `yaml:"title"`
+	}
+
+	input := "<!-- title: frontmatter -->\nrest of the file"
+	rest, err := frontmatter.Parse(strings.NewReader(input), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "" || string(rest) != input {
+		t.Fatalf("HTML comments detected without being enabled: %q", rest)
+	}
+
+	doc, err := frontmatter.MustParseDocument(strings.NewReader(input), &m,
+		&frontmatter.Options{HTMLComments: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "frontmatter" {
+		t.Fatalf("expected title %q, got %q", "frontmatter", m.Title)
+	}
+	if string(doc.Body) != "rest of the file" {
+		t.Fatalf("expected body %q, got %q", "rest of the file", doc.Body)
+	}
+}
+
+func TestPreamble(t *testing.T) {
+	type matter struct {
+		Name string `yaml:"name"`
+	}
+
+	testCases := []struct {
+		input    string
+		preamble *frontmatter.Preamble
+		name     string
+		pre      string
+		body     string
+	}{
+		{
+			input: "#!/usr/bin/env run\n---\nname: frontmatter\n---\nrest of the file",
+			preamble: &frontmatter.Preamble{
+				Lines: 1,
+			},
+			name: "frontmatter",
+			pre:  "#!/usr/bin/env run\n",
+			body: "rest of the file",
+		},
+		{
+			input: "#!/usr/bin/env run\n\n---\nname: frontmatter\n---\nrest of the file",
+			preamble: &frontmatter.Preamble{
+				Pattern: regexp.MustCompile(`^#!`),
+			},
+			name: "frontmatter",
+			pre:  "#!/usr/bin/env run\n\n",
+			body: "rest of the file",
+		},
+		{
+			input: "<!-- prettier-ignore -->\n<!--\n  license\n-->\n# comment\n---\nname: frontmatter\n---\nrest of the file",
+			preamble: &frontmatter.Preamble{
+				Comments: []frontmatter.Comment{
+					{Start: "<!--", End: "-->"},
+					{Start: "#"},
+				},
+			},
+			name: "frontmatter",
+			pre:  "<!-- prettier-ignore -->\n<!--\n  license\n-->\n# comment\n",
+			body: "rest of the file",
+		},
+		{
+			input: "# comment\n---\nname: frontmatter\n---\nrest of the file",
+			body:  "# comment\n---\nname: frontmatter\n---\nrest of the file",
+		},
+		{
+			input: "# comment\nstart of file\n---\nname: frontmatter\n---\nrest of the file",
+			preamble: &frontmatter.Preamble{
+				Comments: []frontmatter.Comment{{Start: "#"}},
+			},
+			body: "# comment\nstart of file\n---\nname: frontmatter\n---\nrest of the file",
+		},
+	}
+
+	for _, tc := range testCases {
+		m := &matter{}
+		doc, err := frontmatter.ParseDocument(strings.NewReader(tc.input), m,
+			&frontmatter.Options{Preamble: tc.preamble})
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", tc.input, err)
+		}
+		if m.Name != tc.name {
+			t.Fatalf("Input: `%s`\n\nexpected name %q, got %q", tc.input, tc.name, m.Name)
+		}
+		if string(doc.Preamble) != tc.pre {
+			t.Fatalf("Input: `%s`\n\nexpected preamble %q, got %q", tc.input, tc.pre, doc.Preamble)
+		}
+		if string(doc.Body) != tc.body {
+			t.Fatalf("Input: `%s`\n\nexpected body %q, got %q", tc.input, tc.body, doc.Body)
+		}
+	}
+}
+
+func TestHeaderFormats(t *testing.T) {
+	type matter struct {
+		Title  string   `yaml:"title"`
+		Author string   `yaml:"author"`
+		Tags   []string `yaml:"tags"`
+		Draft  bool     `yaml:"draft"`
+		Weight int      `yaml:"weight"`
+	}
+
+	testCases := []struct {
+		input  string
+		format *frontmatter.Format
+		matter matter
+		body   string
+		err    bool
+	}{
+		{
+			input:  "#+TITLE: frontmatter\n#+Author: John Doe\n#+TAGS: go\n#+TAGS: org\n#+weight: 10\n\n* rest of the file",
+			format: frontmatter.OrgFormat(),
+			matter: matter{
+				Title:  "frontmatter",
+				Author: "John Doe",
+				Tags:   []string{"go", "org"},
+				Weight: 10,
+			},
+			body: "* rest of the file",
+		},
+		{
+			input:  "\n#+TITLE: frontmatter\n* rest of the file",
+			format: frontmatter.OrgFormat(),
+			matter: matter{Title: "frontmatter"},
+			body:   "* rest of the file",
+		},
+		{
+			input:  "#+TITLE: frontmatter\n#+BEGIN_SRC go\n",
+			format: frontmatter.OrgFormat(),
+			err:    true,
+		},
+		{
+			input:  "= frontmatter\n:author: John Doe\n:draft: true\n:tags: asciidoc\n\nrest of the file",
+			format: frontmatter.AsciiDocFormat(),
+			matter: matter{
+				Title:  "frontmatter",
+				Author: "John Doe",
+				Tags:   []string{"asciidoc"},
+				Draft:  true,
+			},
+			body: "rest of the file",
+		},
+		{
+			input:  "= frontmatter\nJohn Doe <john@doe.com>\nv1.0, 2024-01-02: Draft\n:author: John Doe\n:draft: true\n\nrest of the file",
+			format: frontmatter.AsciiDocFormat(),
+			matter: matter{Title: "frontmatter", Author: "John Doe", Draft: true},
+			body:   "rest of the file",
+		},
+		{
+			input:  "= frontmatter\nJohn Doe\n:tags: asciidoc\n\nrest of the file",
+			format: frontmatter.AsciiDocFormat(),
+			matter: matter{Title: "frontmatter", Tags: []string{"asciidoc"}},
+			body:   "rest of the file",
+		},
+		{
+			input:  "= frontmatter\nJohn Doe\nv1.0\nrest of the file\n:tags: asciidoc",
+			format: frontmatter.AsciiDocFormat(),
+			matter: matter{Title: "frontmatter"},
+			body:   "rest of the file\n:tags: asciidoc",
+		},
+		{
+			input:  ":author: John Doe\n:!draft:\n== rest of the file",
+			format: frontmatter.AsciiDocFormat(),
+			matter: matter{Author: "John Doe"},
+			body:   "== rest of the file",
+		},
+		{
+			input:  ":weight: ten\n\nrest of the file",
+			format: frontmatter.AsciiDocFormat(),
+			err:    true,
+		},
+		{
+			input:  "Title: frontmatter\nAUTHOR: John\n    Doe\nTags: go\nTags: mmd\nDraft: yes?\n\nrest of the file",
+			format: frontmatter.MultiMarkdownFormat(),
+			err:    true,
+		},
+		{
+			input:  "Title: frontmatter\nAUTHOR: John\n    Doe\nTags: go\nTags: mmd\nDraft: true\nWeight: 3\n\nrest of the file",
+			format: frontmatter.MultiMarkdownFormat(),
+			matter: matter{
+				Title:  "frontmatter",
+				Author: "John\nDoe",
+				Tags:   []string{"go", "mmd"},
+				Draft:  true,
+				Weight: 3,
+			},
+			body: "rest of the file",
+		},
+		{
+			input:  "\ntitle: frontmatter\nrest of the file\n\nrest",
+			format: frontmatter.MultiMarkdownFormat(),
+			matter: matter{Title: "frontmatter\nrest of the file"},
+			body:   "rest",
+		},
+		{
+			input:  "# rest of the file\ntitle: frontmatter\n",
+			format: frontmatter.MultiMarkdownFormat(),
+			body:   "# rest of the file\ntitle: frontmatter\n",
+		},
+		{
+			input:  "rest of the file\n:author: John Doe",
+			format: frontmatter.AsciiDocFormat(),
+			body:   "rest of the file\n:author: John Doe",
+		},
+	}
+
+	for _, tc := range testCases {
+		var m matter
+		rest, err := frontmatter.Parse(strings.NewReader(tc.input), &m, tc.format)
+		if tc.err != (err != nil) {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", tc.input, err)
+		}
+		if err != nil {
+			continue
+		}
+		if m.Title != tc.matter.Title || m.Author != tc.matter.Author ||
+			m.Draft != tc.matter.Draft || m.Weight != tc.matter.Weight ||
+			strings.Join(m.Tags, ",") != strings.Join(tc.matter.Tags, ",") {
+			t.Fatalf("Input: `%s`\n\nexpected matter %+v, got %+v", tc.input, tc.matter, m)
+		}
+		if string(rest) != tc.body {
+			t.Fatalf("Input: `%s`\n\nexpected body %q, got %q", tc.input, tc.body, rest)
+		}
+	}
+}
+
+func TestDocumentFormatCopy(t *testing.T) {
+	for _, opts := range []*frontmatter.Options{{}, {Tail: true}} {
+		input := "---\nname: frontmatter\n---\n"
+		doc, err := frontmatter.ParseDocument(strings.NewReader(input), &struct{}{}, opts)
+		if err != nil || doc.Format == nil {
+			t.Fatalf("Input: `%s`\n\nunexpected result: %+v %v", input, doc, err)
+		}
+		doc.Format.Start, doc.Format.End = "+++", "+++"
+
+		if doc, err = frontmatter.ParseDocument(strings.NewReader(input), &struct{}{}, opts); err != nil ||
+			doc.Format == nil || doc.Format.Start != "---" {
+			t.Fatalf("Input: `%s`\n\nexpected default formats to be unchanged: %+v %v", input, doc, err)
+		}
+	}
+}
+
+func TestTail(t *testing.T) {
+	prefixed := func(prefix string, formats []*frontmatter.Format) []*frontmatter.Format {
+		for _, f := range formats {
+			f.Prefix = prefix
+		}
+		return formats
+	}
+
+	type matter struct {
+		Name string `yaml:"name" toml:"name" json:"name"`
+	}
+
+	testCases := []struct {
+		input   string
+		formats []*frontmatter.Format
+		name    string
+		body    string
+		err     bool
+	}{
+		{
+			input: "start of the file\n---\nhorizontal rule\n---\nname: frontmatter\n---\n\n",
+			name:  "frontmatter",
+			body:  "start of the file\n---\nhorizontal rule\n",
+		},
+		{
+			input: "start of the file\n---yaml\nname: frontmatter\n---",
+			name:  "frontmatter",
+			body:  "start of the file\n",
+		},
+		{
+			input: "start of the file\n\n+++\nname = \"frontmatter\"\n+++\n",
+			name:  "frontmatter",
+			body:  "start of the file\n\n",
+		},
+		{
+			input: "start of the file\n{\n  \"name\": \"frontmatter\"\n}\n",
+			name:  "frontmatter",
+			body:  "start of the file\n",
+		},
+		{
+			input:   "start of the file\n<!-- name: frontmatter -->\n",
+			formats: frontmatter.HTMLFormats(),
+			name:    "frontmatter",
+			body:    "start of the file\n",
+		},
+		{
+			input:   "start of the file\n  <!-- name: frontmatter -->  \n\n",
+			formats: frontmatter.HTMLFormats(),
+			name:    "frontmatter",
+			body:    "start of the file\n",
+		},
+		{
+			input:   "start of the file\n# <!-- name: frontmatter -->\n",
+			formats: prefixed("#", frontmatter.HTMLFormats()),
+			name:    "frontmatter",
+			body:    "start of the file\n",
+		},
+		{
+			input:   "start of the file\n// ---\n// name: frontmatter\n// ---\n",
+			formats: frontmatter.LanguageFormats("go"),
+			name:    "frontmatter",
+			body:    "start of the file\n",
+		},
+		{
+			input:   "// ---\nstart of the file\n// name: frontmatter\n// ---\n",
+			formats: frontmatter.LanguageFormats("go"),
+			body:    "// ---\nstart of the file\n// name: frontmatter\n// ---\n",
+		},
+		{
+			input: "---\nname: frontmatter\n---\nrest of the file",
+			body:  "---\nname: frontmatter\n---\nrest of the file",
+		},
+		{
+			input: "start of the file\n+++\nname: frontmatter\n+++\n",
+			err:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		var m matter
+		doc, err := frontmatter.ParseDocument(strings.NewReader(tc.input), &m,
+			&frontmatter.Options{Formats: tc.formats, Tail: true})
+		if tc.err != (err != nil) {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", tc.input, err)
+		}
+		if err != nil {
+			continue
+		}
+		if m.Name != tc.name {
+			t.Fatalf("Input: `%s`\n\nexpected name %q, got %q", tc.input, tc.name, m.Name)
+		}
+		if string(doc.Body) != tc.body {
+			t.Fatalf("Input: `%s`\n\nexpected body %q, got %q", tc.input, tc.body, doc.Body)
+		}
+	}
+
+	_, err := frontmatter.MustParseDocument(strings.NewReader("rest of the file"),
+		&matter{}, &frontmatter.Options{Tail: true})
+	if err != frontmatter.ErrNotFound {
+		t.Fatalf("expected %v, got %v", frontmatter.ErrNotFound, err)
+	}
+}
+
+func TestDocumentMatter(t *testing.T) {
+	testCases := []struct {
+		input  string
+		opts   *frontmatter.Options
+		matter string
+	}{
+		{
+			input:  "---\nname: frontmatter\n---\nrest of the file",
+			matter: "---\nname: frontmatter\n---\n",
+		},
+		{
+			input:  "#!/bin/sh\n+++\nname = \"frontmatter\"\n+++ \n\nrest of the file",
+			opts:   &frontmatter.Options{Preamble: &frontmatter.Preamble{Lines: 1}},
+			matter: "+++\nname = \"frontmatter\"\n+++ \n",
+		},
+		{
+			input:  "{\n  \"name\": \"frontmatter\"\n}\n\nrest of the file",
+			matter: "{\n  \"name\": \"frontmatter\"\n}\n",
+		},
+		{
+			input:  "<!-- name: frontmatter -->\nrest of the file",
+			opts:   &frontmatter.Options{HTMLComments: true},
+			matter: "<!-- name: frontmatter -->\n",
+		},
+		{
+			input:  "#+NAME: frontmatter\n\nrest of the file",
+			opts:   &frontmatter.Options{Formats: []*frontmatter.Format{frontmatter.OrgFormat()}},
+			matter: "#+NAME: frontmatter\n",
+		},
+		{
+			input:  "rest of the file\n---\nname: frontmatter\n---\n\n",
+			opts:   &frontmatter.Options{Tail: true},
+			matter: "---\nname: frontmatter\n---\n",
+		},
+		{
+			input: "rest of the file",
+		},
+	}
+
+	for _, tc := range testCases {
+		var m interface{}
+		doc, err := frontmatter.ParseDocument(strings.NewReader(tc.input), &m, tc.opts)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", tc.input, err)
+		}
+		if string(doc.Matter) != tc.matter {
+			t.Fatalf("Input: `%s`\n\nexpected matter %q, got %q", tc.input, tc.matter, doc.Matter)
+		}
 	}
 }
@@ -0,0 +1,60 @@
+package frontmatter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Digest returns the SHA-256 digest of the specified front matter, encoded
+// as a hexadecimal string. The front matter is canonicalized before it is
+// hashed, so the digest does not depend on the order of the keys or on the
+// format used to encode the front matter: maps are hashed using their
+// sorted keys, and numbers are hashed using their shortest representation
+// (e.g. the integer 1 and the float 1.0 have the same digest). TOML local
+// dates and times are hashed using their textual representation, similar
+// to the YAML and JSON dates, which are decoded as strings.
+func Digest(v interface{}) (string, error) {
+	data, err := json.Marshal(canonical(clone(v)))
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// BodyDigest returns the SHA-256 digest of the document body, encoded as
+// a hexadecimal string.
+func (d *Document) BodyDigest() string {
+	sum := sha256.Sum256(d.Body)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonical converts the values of the specified cloned data to their
+// canonical representation.
+func canonical(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for key, val := range t {
+			t[key] = canonical(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = canonical(val)
+		}
+	case time.Time:
+		switch t.Location().String() {
+		case "date-local":
+			return t.Format("2006-01-02")
+		case "datetime-local":
+			return t.Format("2006-01-02T15:04:05.999999999")
+		case "time-local":
+			return t.Format("15:04:05.999999999")
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+
+	return v
+}
@@ -0,0 +1,81 @@
+package frontmatter_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestDigest(t *testing.T) {
+	inputs := []string{
+		`---
+title: frontmatter
+tags: [go, yaml]
+weight: 1
+ratio: 1.5
+date: 2024-01-02
+author: {name: John Doe, age: 42}
+---
+rest of the file`,
+		`+++
+weight = 1.0
+ratio = 1.5
+date = 2024-01-02
+tags = ["go", "yaml"]
+title = "frontmatter"
+
+[author]
+age = 42
+name = "John Doe"
++++
+rest of the file`,
+		`{
+  "author": {"age": 42, "name": "John Doe"},
+  "date": "2024-01-02",
+  "ratio": 1.5,
+  "tags": ["go", "yaml"],
+  "title": "frontmatter",
+  "weight": 1
+}
+
+rest of the file`,
+	}
+
+	var digest, bodyDigest string
+	for _, input := range inputs {
+		var m interface{}
+		doc, err := frontmatter.ParseDocument(strings.NewReader(input), &m, nil)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+
+		d, err := frontmatter.Digest(m)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if digest == "" {
+			digest, bodyDigest = d, doc.BodyDigest()
+			continue
+		}
+		if d != digest {
+			t.Fatalf("Input: `%s`\n\nexpected digest %s, got %s", input, digest, d)
+		}
+		if doc.BodyDigest() != bodyDigest {
+			t.Fatalf("Input: `%s`\n\nexpected body digest %s, got %s", input, bodyDigest, doc.BodyDigest())
+		}
+	}
+
+	d, err := frontmatter.Digest(frontmatter.Matter{"title": "frontmatter"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d == digest {
+		t.Fatal("expected different digests for different front matters")
+	}
+
+	doc := &frontmatter.Document{Body: []byte("other body")}
+	if doc.BodyDigest() == bodyDigest {
+		t.Fatal("expected different digests for different bodies")
+	}
+}
@@ -0,0 +1,176 @@
+package frontmatter
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OrgFormat returns the format of Org mode documents, whose front matter
+// consists of the contiguous `#+KEY: value` keyword lines at the beginning
+// of the document. Keywords are case-insensitive and are decoded using
+// their lowercase form (e.g. `#+TITLE:` is stored using the `title` key).
+// Keywords which are defined multiple times are decoded as lists.
+func OrgFormat() *Format {
+	return &Format{
+		Prefix:    "#+",
+		Unmarshal: unmarshalOrg,
+	}
+}
+
+// AsciiDocFormat returns the format of AsciiDoc documents, whose front
+// matter consists of an optional `= Title` line, followed by contiguous
+// `:name: value` attribute lines. The title is stored using the `title`
+// key. The optional author and revision lines which follow the title line
+// are skipped. Unset attributes (`:name!:` or `:!name:`) are decoded as
+// `false`.
+func AsciiDocFormat() *Format {
+	return &Format{
+		Prefix:    ":",
+		Title:     "= ",
+		Unmarshal: unmarshalAsciiDoc,
+	}
+}
+
+// MultiMarkdownFormat returns the format of MultiMarkdown and Pelican
+// documents, whose front matter consists of `Key: value` lines, starting at
+// the beginning of the document and ending at the first empty line. Values
+// can span multiple lines, by indenting the continuation lines. Keys are
+// case-insensitive and are decoded using their lowercase form, with spaces
+// removed (e.g. `Base Header Level:` is stored using the `baseheaderlevel`
+// key). Keys which are defined multiple times are decoded as lists.
+func MultiMarkdownFormat() *Format {
+	return &Format{
+		Pattern:     mmdKeyRegexp,
+		Unmarshal:   unmarshalMultiMarkdown,
+		ToEmptyLine: true,
+	}
+}
+
+var mmdKeyRegexp = regexp.MustCompile(`^[[:alnum:]][[:alnum:] _-]*:(\s|$)`)
+
+func unmarshalMultiMarkdown(data []byte, v interface{}) error {
+	var (
+		m    = map[string]interface{}{}
+		key  string
+		vals []string
+	)
+	flush := func() {
+		if key != "" {
+			appendValue(m, key, strings.Join(vals, "\n"))
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			continue
+		}
+
+		// Continuation lines are indented or do not contain a key.
+		if key != "" && (raw[0] == ' ' || raw[0] == '\t' || !mmdKeyRegexp.MatchString(line)) {
+			vals = append(vals, line)
+			continue
+		}
+		if !mmdKeyRegexp.MatchString(line) {
+			return fmt.Errorf("invalid metadata line: %q", line)
+		}
+		flush()
+
+		k, val, _ := strings.Cut(line, ":")
+		key = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(k), " ", ""))
+		vals = vals[:0]
+		if val = strings.TrimSpace(val); val != "" {
+			vals = append(vals, val)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	flush()
+
+	return Decode(m, v)
+}
+
+func unmarshalOrg(data []byte, v interface{}) error {
+	m := map[string]interface{}{}
+	err := scanHeader(data, func(line string) error {
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("invalid keyword line: %q", line)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "" {
+			return fmt.Errorf("invalid keyword line: %q", line)
+		}
+
+		appendValue(m, key, strings.TrimSpace(val))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return Decode(m, v)
+}
+
+func unmarshalAsciiDoc(data []byte, v interface{}) error {
+	m := map[string]interface{}{}
+	err := scanHeader(data, func(line string) error {
+		if title := strings.TrimPrefix(line, "= "); title != line {
+			m["title"] = strings.TrimSpace(title)
+			return nil
+		}
+
+		key, val, ok := strings.Cut(line, ":")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid attribute line: %q", line)
+		}
+
+		switch {
+		case strings.HasPrefix(key, "!"):
+			m[key[1:]] = false
+		case strings.HasSuffix(key, "!"):
+			m[key[:len(key)-1]] = false
+		default:
+			m[key] = strings.TrimSpace(val)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return Decode(m, v)
+}
+
+// scanHeader calls fn for each non-empty line of the specified data.
+func scanHeader(data []byte, fn func(line string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			if err := fn(line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// appendValue stores the specified value in the map. If the key is already
+// defined, its values are stored as a list.
+func appendValue(m map[string]interface{}, key string, val interface{}) {
+	switch prev := m[key].(type) {
+	case nil:
+		m[key] = val
+	case []interface{}:
+		m[key] = append(prev, val)
+	default:
+		m[key] = []interface{}{prev, val}
+	}
+}
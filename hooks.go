@@ -0,0 +1,70 @@
+package frontmatter
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// DecodeHook is called before decoding a value of type `from` into a value
+// of type `to`. It returns the value which is decoded instead of the
+// original `data`, allowing custom type conversions. Hooks which do not
+// handle the specified types must return the data unchanged.
+type DecodeHook func(from, to reflect.Type, data interface{}) (interface{}, error)
+
+var (
+	urlType      = reflect.TypeOf(url.URL{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// StringToURLHook returns a decode hook which converts strings to URLs.
+func StringToURLHook() DecodeHook {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		s, ok := data.(string)
+		if !ok || to != urlType {
+			return data, nil
+		}
+
+		u, err := url.Parse(strings.TrimSpace(s))
+		if err != nil {
+			return nil, err
+		}
+		return *u, nil
+	}
+}
+
+// StringToDurationHook returns a decode hook which converts strings to
+// durations, using the format accepted by `time.ParseDuration` (e.g. `5m`).
+func StringToDurationHook() DecodeHook {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		s, ok := data.(string)
+		if !ok || to != durationType {
+			return data, nil
+		}
+
+		return time.ParseDuration(strings.TrimSpace(s))
+	}
+}
+
+// StringToSliceHook returns a decode hook which splits strings into slices,
+// using the specified separator (e.g. `,`). The elements of the resulting
+// slice are trimmed, and empty strings are converted to empty slices.
+func StringToSliceHook(sep string) DecodeHook {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		s, ok := data.(string)
+		if !ok || to.Kind() != reflect.Slice || to.Elem().Kind() == reflect.Uint8 {
+			return data, nil
+		}
+		if s = strings.TrimSpace(s); s == "" {
+			return []interface{}{}, nil
+		}
+
+		parts := strings.Split(s, sep)
+		items := make([]interface{}, len(parts))
+		for i, part := range parts {
+			items[i] = strings.TrimSpace(part)
+		}
+		return items, nil
+	}
+}
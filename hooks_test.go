@@ -0,0 +1,120 @@
+package frontmatter_test
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestDecodeHooks(t *testing.T) {
+	type matter struct {
+		Link    url.URL       `yaml:"link"`
+		Feed    *url.URL      `yaml:"feed"`
+		Tags    []string      `yaml:"tags"`
+		Timeout time.Duration `yaml:"timeout"`
+		Title   string        `yaml:"title"`
+	}
+
+	opts := &frontmatter.Options{
+		DecodeHooks: []frontmatter.DecodeHook{
+			frontmatter.StringToURLHook(),
+			frontmatter.StringToSliceHook(","),
+			frontmatter.StringToDurationHook(),
+		},
+	}
+
+	inputs := []string{
+		`---
+link: https://example.com/post
+feed: https://example.com/feed.xml
+tags: go, yaml , toml
+timeout: 5m
+title: a, b
+---
+rest of the file`,
+		`+++
+link = "https://example.com/post"
+feed = "https://example.com/feed.xml"
+tags = "go, yaml , toml"
+timeout = "5m"
+title = "a, b"
++++
+rest of the file`,
+		`{
+  "link": "https://example.com/post",
+  "feed": "https://example.com/feed.xml",
+  "tags": "go, yaml , toml",
+  "timeout": "5m",
+  "title": "a, b"
+}
+
+rest of the file`,
+	}
+
+	for _, input := range inputs {
+		var m matter
+		doc, err := frontmatter.ParseDocument(strings.NewReader(input), &m, opts)
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if doc.Format == nil || string(doc.Body) != "rest of the file" {
+			t.Fatalf("Input: `%s`\n\nunexpected document: %+v", input, doc)
+		}
+
+		checks := []struct {
+			exp, act interface{}
+		}{
+			{"https://example.com/post", m.Link.String()},
+			{"https://example.com/feed.xml", m.Feed.String()},
+			{[]string{"go", "yaml", "toml"}, m.Tags},
+			{5 * time.Minute, m.Timeout},
+			{"a, b", m.Title},
+		}
+		for i, check := range checks {
+			if !reflect.DeepEqual(check.exp, check.act) {
+				t.Fatalf("Input: `%s`\n\ncheck %d: expected %v, got %v", input, i, check.exp, check.act)
+			}
+		}
+	}
+
+	// Hook errors.
+	var m matter
+	input := "---\ntimeout: soon\n---\n"
+	if _, err := frontmatter.ParseDocument(strings.NewReader(input), &m, opts); err == nil {
+		t.Fatalf("Input: `%s`\n\nexpected error", input)
+	}
+
+	// Front matter not found.
+	input = "rest of the file"
+	if _, err := frontmatter.MustParseDocument(strings.NewReader(input), &m, opts); err != frontmatter.ErrNotFound {
+		t.Fatalf("Input: `%s`\n\nexpected error %v, got %v", input, frontmatter.ErrNotFound, err)
+	}
+}
+
+func TestDecodeWithHooks(t *testing.T) {
+	upper := func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if s, ok := data.(string); ok && to.Kind() == reflect.String {
+			return strings.ToUpper(s), nil
+		}
+		return data, nil
+	}
+
+	var m struct {
+		Title string   `yaml:"title"`
+		Tags  []string `yaml:"tags"`
+		Count int      `yaml:"count"`
+	}
+	src := map[string]interface{}{"title": "post", "tags": "go,yaml", "count": "3"}
+
+	err := frontmatter.DecodeWithHooks(src, &m, frontmatter.StringToSliceHook(","), upper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Title != "POST" || !reflect.DeepEqual(m.Tags, []string{"GO", "YAML"}) || m.Count != 3 {
+		t.Fatalf("unexpected result: %+v", m)
+	}
+}
@@ -0,0 +1,195 @@
+package frontmatter
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Includes holds the configuration used to compose front matters from
+// shared files. A front matter map (or any of its nested maps) can include
+// one or more files using a reserved key, whose value is a path or a list
+// of paths, relative to the including file. E.g.:
+//
+//	$include: ../shared/seo.yaml
+//	author:
+//	  $include: [../shared/author.yaml, ../shared/social.json]
+//
+// Data files are decoded using the unmarshaler registered for their
+// extension, while the front matter of other files (e.g. Markdown
+// documents) is parsed using the default formats. Included files can
+// include other files as well. The included values are merged in order,
+// each file overriding the values of the previous ones, and the values of
+// the including map override all the included values. The reserved key is
+// removed from the result.
+type Includes struct {
+	// FS is the file system containing the included files. If nil, it is
+	// set to the file system of the documents parsed by `Cascade` and
+	// `Sidecar`. Otherwise, resolving an include returns an error.
+	FS fs.FS
+
+	// Path is the path of the document within the file system, used to
+	// resolve the relative paths of the included files. If empty, paths
+	// are resolved relative to the root of the file system. It is set
+	// automatically for the documents parsed by `Cascade` and `Sidecar`.
+	Path string
+
+	// Key defines the reserved key used to include files.
+	// If empty, `$include` is used.
+	Key string
+
+	// MaxDepth defines the maximum depth of nested includes.
+	// If 0, a depth of 10 is used.
+	MaxDepth int
+
+	// Unmarshalers maps file extensions to the unmarshal functions used to
+	// decode the included data files. If nil, the YAML (`.yaml` and `.yml`),
+	// TOML (`.toml`) and JSON (`.json`) unmarshalers are used.
+	Unmarshalers map[string]UnmarshalFunc
+
+	// Merge defines how the included values are merged.
+	Merge MergeOptions
+}
+
+// Resolve returns a copy of the specified front matter, having its
+// includes resolved. The passed in front matter is not modified.
+func (inc *Includes) Resolve(m Matter) (Matter, error) {
+	v, err := inc.resolve(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return Matter(v.(map[string]interface{})), nil
+}
+
+func (inc *Includes) resolve(data interface{}) (interface{}, error) {
+	name := path.Clean("/" + inc.Path)[1:]
+	if name == "" {
+		name = "."
+	}
+
+	return inc.resolveValue(clone(data), name, []string{name})
+}
+
+func (inc *Includes) resolveValue(val interface{}, name string, stack []string) (interface{}, error) {
+	switch t := val.(type) {
+	case map[string]interface{}:
+		return inc.resolveMap(t, name, stack)
+	case []interface{}:
+		for i, item := range t {
+			v, err := inc.resolveValue(item, name, stack)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = v
+		}
+	}
+
+	return val, nil
+}
+
+func (inc *Includes) resolveMap(m map[string]interface{}, name string, stack []string) (map[string]interface{}, error) {
+	key := inc.Key
+	if key == "" {
+		key = "$include"
+	}
+
+	// Resolve the includes of the nested values.
+	for k, val := range m {
+		v, err := inc.resolveValue(val, name, stack)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+
+	spec, ok := m[key]
+	if !ok {
+		return m, nil
+	}
+	delete(m, key)
+
+	var paths []string
+	switch t := spec.(type) {
+	case string:
+		paths = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			p, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s: invalid %s value %v", name, key, spec)
+			}
+			paths = append(paths, p)
+		}
+	default:
+		return nil, fmt.Errorf("%s: invalid %s value %v", name, key, spec)
+	}
+
+	maxDepth := inc.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 10
+	}
+
+	base := Matter{}
+	for _, p := range paths {
+		target := path.Join(path.Dir(name), p)
+		if strings.HasPrefix(p, "/") {
+			target = path.Clean(p)[1:]
+		}
+		if !fs.ValidPath(target) {
+			return nil, fmt.Errorf("%s: invalid include path %q", name, p)
+		}
+
+		for i, s := range stack {
+			if s == target {
+				cycle := append(append([]string{}, stack[i:]...), target)
+				return nil, fmt.Errorf("%s: include cycle %s", name, strings.Join(cycle, " -> "))
+			}
+		}
+		if len(stack) > maxDepth {
+			return nil, fmt.Errorf("%s: maximum include depth %d exceeded", name, maxDepth)
+		}
+
+		vals, err := inc.load(target)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		sub := append(stack[:len(stack):len(stack)], target)
+		resolved, err := inc.resolveMap(vals, target, sub)
+		if err != nil {
+			return nil, err
+		}
+		base = Merge(base, resolved, &inc.Merge)
+	}
+
+	return Merge(base, m, &inc.Merge), nil
+}
+
+// errNilIncludesFS is returned when including a file without a file system.
+var errNilIncludesFS = errors.New("frontmatter: includes file system not specified")
+
+// load decodes the specified included file.
+func (inc *Includes) load(name string) (map[string]interface{}, error) {
+	if inc.FS == nil {
+		return nil, errNilIncludesFS
+	}
+
+	unmarshalers := inc.Unmarshalers
+	if unmarshalers == nil {
+		unmarshalers = dataUnmarshalers
+	}
+
+	if _, ok := unmarshalers[strings.ToLower(path.Ext(name))]; !ok {
+		m, _, err := parseFile(inc.FS, nil, name)
+		return m, err
+	}
+
+	m, err := readData(inc.FS, unmarshalers, name)
+	if err == nil && m == nil {
+		err = fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+	return m, err
+}
@@ -0,0 +1,113 @@
+package frontmatter_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestIncludes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"shared/seo.yaml":     {Data: []byte("description: Shared\nrobots: index\nkeywords: [go]\n")},
+		"shared/author.toml":  {Data: []byte("\"$include\" = \"social.json\"\nname = \"John Doe\"\n")},
+		"shared/social.json":  {Data: []byte(`{"twitter": "@john", "name": "Unknown"}`)},
+		"shared/base.md":      {Data: []byte("---\nlayout: post\n---\nbase")},
+		"shared/a.yaml":       {Data: []byte("$include: b.yaml\n")},
+		"shared/b.yaml":       {Data: []byte("$include: a.yaml\n")},
+		"shared/c.yaml":       {Data: []byte("$include: d.yaml\n")},
+		"shared/d.yaml":       {Data: []byte("value: 1\n")},
+		"shared/invalid.yaml": {Data: []byte("- item\n")},
+		"docs/post.md": {Data: []byte(`---
+$include: [../shared/seo.yaml, /shared/base.md]
+title: Post
+robots: noindex
+author:
+  $include: ../shared/author.toml
+---
+rest of the file`)},
+	}
+
+	type author struct {
+		Name    string `yaml:"name"`
+		Twitter string `yaml:"twitter"`
+	}
+	type matter struct {
+		Title       string   `yaml:"title"`
+		Description string   `yaml:"description"`
+		Robots      string   `yaml:"robots"`
+		Keywords    []string `yaml:"keywords"`
+		Layout      string   `yaml:"layout"`
+		Author      author   `yaml:"author"`
+	}
+	exp := matter{
+		Title:       "Post",
+		Description: "Shared",
+		Robots:      "noindex",
+		Keywords:    []string{"go"},
+		Layout:      "post",
+		Author:      author{Name: "John Doe", Twitter: "@john"},
+	}
+
+	var m matter
+	opts := &frontmatter.Options{Includes: &frontmatter.Includes{FS: fsys, Path: "docs/post.md"}}
+	input, _ := fsys.ReadFile("docs/post.md")
+	if _, err := frontmatter.ParseDocument(strings.NewReader(string(input)), &m, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m, exp) {
+		t.Fatalf("expected %+v, got %+v", exp, m)
+	}
+
+	// Includes resolved relative to the documents of a cascade.
+	c := &frontmatter.Cascade{FS: fsys, Options: &frontmatter.Options{Includes: &frontmatter.Includes{}}}
+	res, _, err := c.Resolve("docs/post.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.String("author.twitter") != "@john" || res.Has("$include") || res.Has("author.$include") {
+		t.Fatalf("unexpected front matter: %v", res)
+	}
+
+	// Errors.
+	testCases := []struct {
+		matter   frontmatter.Matter
+		maxDepth int
+		err      string
+	}{
+		{frontmatter.Matter{"$include": "shared/missing.yaml"}, 0, "file does not exist"},
+		{frontmatter.Matter{"$include": "shared/a.yaml"}, 0, "include cycle shared/a.yaml -> shared/b.yaml -> shared/a.yaml"},
+		{frontmatter.Matter{"$include": "shared/c.yaml"}, 1, "maximum include depth 1 exceeded"},
+		{frontmatter.Matter{"$include": 42}, 0, "invalid $include value"},
+		{frontmatter.Matter{"$include": "../outside.yaml"}, 0, "invalid include path"},
+		{frontmatter.Matter{"$include": "shared/invalid.yaml"}, 0, "cannot use []interface {} as front matter"},
+	}
+	for _, tc := range testCases {
+		inc := &frontmatter.Includes{FS: fsys, MaxDepth: tc.maxDepth}
+		if _, err := inc.Resolve(tc.matter); err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Fatalf("%v: expected error %q, got %v", tc.matter, tc.err, err)
+		}
+	}
+
+	// The depth limit allows the configured number of nested includes.
+	inc := &frontmatter.Includes{FS: fsys, MaxDepth: 2}
+	if res, err := inc.Resolve(frontmatter.Matter{"$include": "shared/c.yaml"}); err != nil || res.Int("value") != 1 {
+		t.Fatalf("unexpected result: %v %v", res, err)
+	}
+
+	// Documents parsed without a file system can only include files if one
+	// is provided.
+	var doc frontmatter.Matter
+	opts = &frontmatter.Options{Includes: &frontmatter.Includes{}}
+	input = []byte("---\n$include: a.yaml\n---\n")
+	_, err = frontmatter.ParseDocument(strings.NewReader(string(input)), &doc, opts)
+	if err == nil || !strings.Contains(err.Error(), "frontmatter: includes file system not specified") {
+		t.Fatalf("Input: `%s`\n\nexpected nil file system error, got %v", input, err)
+	}
+	input = []byte("---\ntitle: Post\n---\n")
+	if _, err := frontmatter.ParseDocument(strings.NewReader(string(input)), &doc, opts); err != nil || doc.String("title") != "Post" {
+		t.Fatalf("Input: `%s`\n\nunexpected result: %v %v", input, doc, err)
+	}
+}
@@ -0,0 +1,443 @@
+// Package index implements an in-memory index of the front matter of the
+// documents contained by a file system, which is persisted between runs.
+//
+// The index is stored in a single local file, managed by a
+// `frontmatter.Cache`: saving the index only appends the records of the
+// documents which changed, so no external database is required. The index
+// file does not contain lookup structures. Opening the index decodes the
+// whole file and rebuilds the records and the field value tables of all the
+// documents in memory, so the startup time and the memory usage grow with
+// the number of indexed documents and the size of their front matter.
+// Lookups use the in-memory value tables, while queries are evaluated
+// against every record. The index is updated incrementally: only the
+// documents whose modification time or size changed are read again, and
+// only the documents whose content digest changed are parsed again. E.g.:
+//
+//	idx, err := index.Open(".frontmatter.idx", os.DirFS("content"), nil)
+//	if err != nil {
+//		// Treat error.
+//	}
+//	if _, err := idx.Update("."); err != nil {
+//		// Treat error.
+//	}
+//	if err := idx.Save(); err != nil {
+//		// Treat error.
+//	}
+//
+//	drafts := idx.Lookup("draft", "true")
+//	results, err := idx.Query(query.MustParse(`"go" in tags`))
+package index
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/query"
+)
+
+// Record contains the indexed information of a document.
+type Record struct {
+	// Path is the slash-separated path of the document.
+	Path string
+
+	// Format is the start delimiter of the front matter format of the
+	// document (e.g. `---` or `+++`), or its line prefix for header front
+	// matters. It is empty if the document does not have front matter.
+	Format string
+
+	// ModTime is the modification time of the document.
+	ModTime time.Time
+
+	// Size is the size of the document, in bytes.
+	Size int64
+
+	// Hash is the SHA-256 digest of the document content.
+	Hash string
+
+	// Matter is the decoded front matter of the document.
+	// It must not be modified.
+	Matter frontmatter.Matter
+
+	// Fields maps the dotted paths of the front matter values to their
+	// textual representation, as returned by `Flatten`.
+	Fields map[string][]string
+}
+
+// Stats reports the changes applied by an update.
+type Stats struct {
+	// Added is the number of new documents.
+	Added int
+
+	// Updated is the number of documents whose content changed.
+	Updated int
+
+	// Removed is the number of documents which no longer exist.
+	Removed int
+
+	// Unchanged is the number of documents whose content did not change.
+	Unchanged int
+}
+
+// Options holds the configuration used to index documents.
+type Options struct {
+	// Pattern defines the pattern matched against the names of the
+	// documents, using the syntax of `path.Match`. If empty, `*.md` is used.
+	Pattern string
+
+	// Parse holds the configuration used to parse the documents.
+	Parse *frontmatter.Options
+}
+
+// Index is a persistent index of front matter. Index methods are safe
+// for concurrent use.
+type Index struct {
+	cache   *frontmatter.Cache
+	fsys    fs.FS
+	opts    Options
+	mu      sync.RWMutex
+	records map[string]*Record
+	values  map[string]map[string]map[string]struct{}
+}
+
+// Open returns the index stored in the specified file, containing the
+// documents of the specified file system. If the index file does not
+// exist, or if it cannot be decoded, the index starts empty. All the
+// records of the index file are loaded in memory.
+func Open(file string, fsys fs.FS, opts *Options) (*Index, error) {
+	x := &Index{
+		fsys:    fsys,
+		records: map[string]*Record{},
+		values:  map[string]map[string]map[string]struct{}{},
+	}
+	if opts != nil {
+		x.opts = *opts
+	}
+
+	cache, err := frontmatter.OpenCache(file, fsys, x.opts.Parse)
+	if err != nil {
+		return nil, err
+	}
+	x.cache = cache
+
+	for _, name := range cache.Names() {
+		x.add(newRecord(name, cache.Entry(name)))
+	}
+
+	return x, nil
+}
+
+// Update indexes the documents found in the specified directory and its
+// subdirectories, and removes the records of the documents below the
+// directory which no longer exist. The update is stopped at the first
+// document which cannot be read or parsed, keeping the changes applied
+// up to that point.
+func (x *Index) Update(root string) (Stats, error) {
+	var stats Stats
+	seen := map[string]struct{}{}
+
+	err := frontmatter.Walk(x.fsys, root, x.opts.Pattern, func(name string, d fs.DirEntry) error {
+		seen[name] = struct{}{}
+
+		entry, _, err := x.cache.Load(name)
+		if err != nil {
+			return err
+		}
+
+		x.mu.Lock()
+		defer x.mu.Unlock()
+
+		prev := x.records[name]
+		switch {
+		case prev == nil:
+			stats.Added++
+		case prev.Hash != entry.Hash:
+			stats.Updated++
+		default:
+			stats.Unchanged++
+		}
+
+		var rec *Record
+		if prev != nil && prev.Hash == entry.Hash {
+			// The content did not change, so the fields are reused.
+			r := *prev
+			r.ModTime, r.Size = entry.ModTime, entry.Size
+			rec = &r
+		} else {
+			rec = newRecord(name, entry)
+		}
+
+		x.remove(name)
+		x.add(rec)
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	root = path.Clean(root)
+	for name := range x.records {
+		if _, ok := seen[name]; ok || !contains(root, name) {
+			continue
+		}
+
+		x.remove(name)
+		x.cache.Remove(name)
+		stats.Removed++
+	}
+
+	return stats, nil
+}
+
+// Save writes the changes of the index to its file, if any. Only the
+// records of the changed documents are written, unless the file has to
+// be compacted, in which case it is replaced atomically.
+func (x *Index) Save() error {
+	return x.cache.Save()
+}
+
+// Len returns the number of indexed documents.
+func (x *Index) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.records)
+}
+
+// Get returns the record of the specified document, or nil if the document
+// is not indexed. The record must not be modified.
+func (x *Index) Get(name string) *Record {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return x.records[name]
+}
+
+// Fields returns the sorted dotted paths of the fields which have values
+// in the indexed documents.
+func (x *Index) Fields() []string {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	fields := make([]string, 0, len(x.values))
+	for field := range x.values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// Values returns the values of the specified field, mapped to the number
+// of documents containing them.
+func (x *Index) Values(field string) map[string]int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	values := make(map[string]int, len(x.values[field]))
+	for val, names := range x.values[field] {
+		values[val] = len(names)
+	}
+
+	return values
+}
+
+// Lookup returns the sorted paths of the documents in which the specified
+// field has the specified value. The value is compared with the textual
+// representation of the field values, as returned by `Flatten`. For list
+// fields, documents containing the value in the list are returned.
+func (x *Index) Lookup(field, value string) []string {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	names := make([]string, 0, len(x.values[field][value]))
+	for name := range x.values[field][value] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Query returns the indexed documents whose front matter matches the
+// specified query, sorted by path. The query is evaluated against the front
+// matter of every indexed document.
+func (x *Index) Query(q *query.Query) ([]query.Result, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var results []query.Result
+	for name, rec := range x.records {
+		ok, err := q.Match(rec.Matter)
+		if err != nil {
+			return nil, &fs.PathError{Op: "query", Path: name, Err: err}
+		}
+		if ok {
+			results = append(results, query.Result{Name: name, Matter: rec.Matter})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	return results, nil
+}
+
+// newRecord returns the record of the specified document, based on its
+// cache entry.
+func newRecord(name string, entry *frontmatter.CacheEntry) *Record {
+	return &Record{
+		Path:    name,
+		Format:  entry.Format,
+		ModTime: entry.ModTime,
+		Size:    entry.Size,
+		Hash:    entry.Hash,
+		Matter:  entry.Matter,
+		Fields:  Flatten(entry.Matter),
+	}
+}
+
+// add adds the specified record to the index. The caller must hold the
+// write lock.
+func (x *Index) add(rec *Record) {
+	x.records[rec.Path] = rec
+	for field, vals := range rec.Fields {
+		if len(vals) == 0 {
+			continue
+		}
+
+		values := x.values[field]
+		if values == nil {
+			values = map[string]map[string]struct{}{}
+			x.values[field] = values
+		}
+
+		for _, val := range vals {
+			names := values[val]
+			if names == nil {
+				names = map[string]struct{}{}
+				values[val] = names
+			}
+			names[rec.Path] = struct{}{}
+		}
+	}
+}
+
+// remove removes the record of the specified document from the index,
+// if it exists. The caller must hold the write lock.
+func (x *Index) remove(name string) {
+	rec, ok := x.records[name]
+	if !ok {
+		return
+	}
+	delete(x.records, name)
+
+	for field, vals := range rec.Fields {
+		values := x.values[field]
+		for _, val := range vals {
+			delete(values[val], name)
+			if len(values[val]) == 0 {
+				delete(values, val)
+			}
+		}
+		if len(values) == 0 {
+			delete(x.values, field)
+		}
+	}
+}
+
+// Flatten maps the dotted paths of the values of the specified front matter
+// to their textual representation. The elements of lists share the path of
+// the list, and empty lists and maps are mapped to no values. Numbers,
+// booleans and `null` use their JSON representation. TOML local dates and
+// times use their textual representation, and other times are formatted
+// using the RFC 3339 layout. E.g.:
+//
+//	{"tags": ["go", "yaml"], "author": {"name": "John"}, "weight": 1.0}
+//
+// is flattened into:
+//
+//	{"tags": ["go", "yaml"], "author.name": ["John"], "weight": ["1"]}
+func Flatten(m frontmatter.Matter) map[string][]string {
+	fields := map[string][]string{}
+	for key, val := range m {
+		flatten(fields, key, val)
+	}
+
+	return fields
+}
+
+func flatten(fields map[string][]string, key string, v interface{}) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		if len(t) == 0 {
+			addField(fields, key)
+		}
+		for k, val := range t {
+			flatten(fields, key+"."+k, val)
+		}
+	case frontmatter.Matter:
+		flatten(fields, key, map[string]interface{}(t))
+	case []interface{}:
+		if len(t) == 0 {
+			addField(fields, key)
+		}
+		for _, val := range t {
+			flatten(fields, key, val)
+		}
+	default:
+		fields[key] = append(fields[key], format(t))
+	}
+}
+
+// addField adds the specified path to the fields, without values.
+func addField(fields map[string][]string, key string) {
+	if _, ok := fields[key]; !ok {
+		fields[key] = nil
+	}
+}
+
+func format(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case int:
+		return strconv.Itoa(t)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case uint64:
+		return strconv.FormatUint(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case time.Time:
+		switch t.Location().String() {
+		case "date-local":
+			return t.Format("2006-01-02")
+		case "datetime-local":
+			return t.Format("2006-01-02T15:04:05.999999999")
+		case "time-local":
+			return t.Format("15:04:05.999999999")
+		}
+		return t.Format(time.RFC3339Nano)
+	}
+
+	return ""
+}
+
+// contains reports whether the specified slash-separated path is contained
+// by the root directory.
+func contains(root, name string) bool {
+	return root == "." || name == root || strings.HasPrefix(name, root+"/")
+}
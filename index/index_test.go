@@ -0,0 +1,156 @@
+package index_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/index"
+	"github.com/adrg/frontmatter/query"
+)
+
+func TestFlatten(t *testing.T) {
+	m := frontmatter.Matter{
+		"title":  "Post",
+		"tags":   []interface{}{"go", "yaml"},
+		"author": map[string]interface{}{"name": "John", "links": []interface{}{}},
+		"weight": 1.0,
+		"count":  int64(3),
+		"draft":  false,
+		"image":  nil,
+		"date":   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"posts":  []interface{}{map[string]interface{}{"id": int64(1)}, map[string]interface{}{"id": int64(2)}},
+	}
+
+	expected := map[string][]string{
+		"title":        {"Post"},
+		"tags":         {"go", "yaml"},
+		"author.name":  {"John"},
+		"author.links": nil,
+		"weight":       {"1"},
+		"count":        {"3"},
+		"draft":        {"false"},
+		"image":        {"null"},
+		"date":         {"2024-01-02T03:04:05Z"},
+		"posts.id":     {"1", "2"},
+	}
+	if fields := index.Flatten(m); !reflect.DeepEqual(fields, expected) {
+		t.Fatalf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	dir := t.TempDir()
+	content := filepath.Join(dir, "content")
+	file := filepath.Join(dir, "index")
+
+	write := func(name, data string, mtime time.Time) {
+		name = filepath.Join(content, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mtime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write("a.md", "---\ntitle: A\ntags: [go, yaml]\ndraft: true\n---\nbody", mtime)
+	write("blog/b.md", "+++\ntitle = \"B\"\ndate = 2024-05-06\ntags = [\"go\"]\n+++\n", mtime)
+	write("blog/c.md", "no front matter", mtime)
+	write("notes.txt", "---\ntitle: ignored\n---\n", mtime)
+
+	open := func() *index.Index {
+		idx, err := index.Open(file, os.DirFS(content), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return idx
+	}
+	update := func(idx *index.Index, root string, expected index.Stats) {
+		stats, err := idx.Update(root)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stats != expected {
+			t.Fatalf("expected stats %+v, got %+v", expected, stats)
+		}
+		if err := idx.Save(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	idx := open()
+	update(idx, ".", index.Stats{Added: 3})
+
+	// Reopen the index, in order to answer queries using the stored data.
+	os.Rename(content, content+".bak")
+	idx = open()
+	if idx.Len() != 3 {
+		t.Fatalf("expected 3 documents, got %d", idx.Len())
+	}
+	if names := idx.Lookup("tags", "go"); !reflect.DeepEqual(names, []string{"a.md", "blog/b.md"}) {
+		t.Fatalf("unexpected lookup results: %v", names)
+	}
+	if names := idx.Lookup("date", "2024-05-06"); !reflect.DeepEqual(names, []string{"blog/b.md"}) {
+		t.Fatalf("unexpected lookup results: %v", names)
+	}
+	if values := idx.Values("tags"); !reflect.DeepEqual(values, map[string]int{"go": 2, "yaml": 1}) {
+		t.Fatalf("unexpected values: %v", values)
+	}
+	if fields := idx.Fields(); !reflect.DeepEqual(fields, []string{"date", "draft", "tags", "title"}) {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+	if rec := idx.Get("blog/b.md"); rec == nil || rec.Format != "+++" || rec.Matter.String("title") != "B" {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+	if rec := idx.Get("blog/c.md"); rec == nil || rec.Format != "" {
+		t.Fatalf("unexpected record: %+v", rec)
+	}
+
+	results, err := idx.Query(query.MustParse(`"go" in tags && date > 2024-01-01`))
+	if err != nil || len(results) != 1 || results[0].Name != "blog/b.md" {
+		t.Fatalf("unexpected query results: %v %v", results, err)
+	}
+	os.Rename(content+".bak", content)
+
+	// Touched documents are read again, but parsed only if they changed.
+	mtime = mtime.Add(time.Minute)
+	write("a.md", "---\ntitle: A\ntags: [go, yaml]\ndraft: true\n---\nbody", mtime)
+	write("blog/b.md", "+++\ntitle = \"B\"\ntags = [\"toml\"]\n+++\n", mtime)
+	os.Remove(filepath.Join(content, "blog", "c.md"))
+	write("blog/d.md", "---\ntitle: D\n---\n", mtime)
+
+	update(idx, "blog", index.Stats{Added: 1, Updated: 1, Removed: 1})
+	update(idx, ".", index.Stats{Unchanged: 3})
+
+	idx = open()
+	if names := idx.Lookup("tags", "go"); !reflect.DeepEqual(names, []string{"a.md"}) {
+		t.Fatalf("unexpected lookup results: %v", names)
+	}
+	if names := idx.Lookup("date", "2024-05-06"); len(names) != 0 {
+		t.Fatalf("unexpected lookup results: %v", names)
+	}
+	if idx.Get("blog/c.md") != nil || idx.Get("blog/d.md") == nil {
+		t.Fatal("expected index to reflect removed and added documents")
+	}
+
+	write("invalid.md", "---\ntitle: [\n---\n", mtime)
+	if _, err := idx.Update("."); err == nil {
+		t.Fatal("expected error for invalid document")
+	}
+
+	// Index files which cannot be decoded are ignored.
+	if err := os.WriteFile(file, []byte("invalid"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if idx := open(); idx.Len() != 0 {
+		t.Fatalf("expected empty index, got %d documents", idx.Len())
+	}
+}
@@ -0,0 +1,271 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Interpolation holds the configuration used to replace the placeholders
+// contained by the string values of a front matter. The supported
+// placeholders are:
+//   - `${name}`: replaced with the value of the specified variable or
+//     environment variable. A default value can be provided using the
+//     `${name:-default}` syntax.
+//   - `{{ .key.path }}`: replaced with the value found at the specified
+//     path of the front matter, or of the variables, if the front matter
+//     does not contain the path.
+//   - `$$`: replaced with a literal `$`.
+//
+// If a string value consists of a single placeholder, it is replaced with
+// the referenced value, preserving its type. Otherwise, the referenced
+// values must be scalar values, which are formatted as strings.
+type Interpolation struct {
+	// Env specifies whether `${name}` placeholders can reference
+	// environment variables.
+	Env bool
+
+	// LookupEnv retrieves the value of the specified environment variable.
+	// If nil, `os.LookupEnv` is used.
+	LookupEnv func(key string) (string, bool)
+
+	// Vars defines the variables which can be referenced by placeholders.
+	// Nested values are referenced using dotted paths (e.g. `site.title`).
+	// Variables take precedence over environment variables.
+	Vars map[string]interface{}
+}
+
+// InterpolationError is reported when a placeholder cannot be replaced.
+type InterpolationError struct {
+	// Line is the line of the document containing the placeholder.
+	// It is 0 if the line is unknown.
+	Line int
+
+	// Key is the path of the value containing the placeholder.
+	Key string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *InterpolationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s: %v", e.Line, e.Key, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", e.Key, e.Err)
+}
+
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
+
+var placeholderRegexp = regexp.MustCompile(`\$\$|\$\{([^{}]*)\}|\{\{\s*\.([[:alnum:]_][^\s{}]*)\s*\}\}`)
+
+// Interpolate returns a copy of the specified front matter, having the
+// placeholders of its string values replaced. The passed in front matter
+// is not modified.
+func (in *Interpolation) Interpolate(m Matter) (Matter, error) {
+	v, err := in.interpolate(m, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return Matter(v.(map[string]interface{})), nil
+}
+
+// interpolate replaces the placeholders of the specified data. The raw
+// front matter is used to determine the line of the placeholders which
+// cannot be replaced, and line is the number of the first raw line.
+func (in *Interpolation) interpolate(data interface{}, raw []byte, line int) (interface{}, error) {
+	data = clone(data)
+	root, _ := data.(map[string]interface{})
+
+	ip := &interpolator{
+		Interpolation: in,
+		root:          Matter(root),
+		raw:           raw,
+		line:          line,
+		values:        map[string]interface{}{},
+	}
+	return ip.resolve("", data)
+}
+
+type interpolator struct {
+	*Interpolation
+	root   Matter
+	raw    []byte
+	line   int
+	values map[string]interface{}
+	stack  []string
+}
+
+// resolve returns the interpolated value found at the specified path.
+func (ip *interpolator) resolve(path string, val interface{}) (interface{}, error) {
+	if v, ok := ip.values[path]; ok {
+		return v, nil
+	}
+	for i, p := range ip.stack {
+		if p == path {
+			cycle := append(append([]string{}, ip.stack[i:]...), path)
+			return nil, fmt.Errorf("reference cycle %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	ip.stack = append(ip.stack, path)
+	defer func() { ip.stack = ip.stack[:len(ip.stack)-1] }()
+
+	var err error
+	switch t := val.(type) {
+	case map[string]interface{}:
+		// Resolve the keys in order, in order to report errors consistently.
+		m := make(map[string]interface{}, len(t))
+		for _, key := range Matter(t).Keys() {
+			if m[key], err = ip.resolve(joinPath(path, key), t[key]); err != nil {
+				return nil, err
+			}
+		}
+		val = m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, v := range t {
+			if s[i], err = ip.resolve(joinPath(path, strconv.Itoa(i)), v); err != nil {
+				return nil, err
+			}
+		}
+		val = s
+	case string:
+		if val, err = ip.expand(path, t); err != nil {
+			return nil, err
+		}
+	}
+
+	ip.values[path] = val
+	return val, nil
+}
+
+// expand replaces the placeholders of the specified string.
+func (ip *interpolator) expand(path, s string) (interface{}, error) {
+	matches := placeholderRegexp.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	prev := 0
+	for _, match := range matches {
+		placeholder := s[match[0]:match[1]]
+		sb.WriteString(s[prev:match[0]])
+		prev = match[1]
+
+		if placeholder == "$$" {
+			sb.WriteByte('$')
+			continue
+		}
+
+		var val interface{}
+		var err error
+		if match[2] >= 0 {
+			val, err = ip.variable(s[match[2]:match[3]])
+		} else {
+			val, err = ip.reference(s[match[4]:match[5]])
+		}
+		if err != nil {
+			var ierr *InterpolationError
+			if errors.As(err, &ierr) {
+				return nil, err
+			}
+			return nil, ip.errorf(path, placeholder, err)
+		}
+
+		// Preserve the type of values consisting of a single placeholder.
+		if len(matches) == 1 && match[0] == 0 && match[1] == len(s) {
+			return val, nil
+		}
+
+		str, ok := toString(val)
+		if !ok {
+			return nil, ip.errorf(path, placeholder,
+				fmt.Errorf("cannot interpolate %T into string", val))
+		}
+		sb.WriteString(str)
+	}
+	sb.WriteString(s[prev:])
+
+	return sb.String(), nil
+}
+
+// variable returns the value of the specified variable.
+func (ip *interpolator) variable(name string) (interface{}, error) {
+	name, def, hasDef := strings.Cut(name, ":-")
+	if name = strings.TrimSpace(name); name == "" {
+		return nil, errors.New("empty variable name")
+	}
+
+	if val, ok := Matter(ip.Vars).Get(name); ok {
+		return val, nil
+	}
+	if ip.Env {
+		lookup := ip.LookupEnv
+		if lookup == nil {
+			lookup = os.LookupEnv
+		}
+		if val, ok := lookup(name); ok {
+			return val, nil
+		}
+	}
+	if hasDef {
+		return def, nil
+	}
+
+	return nil, fmt.Errorf("undefined variable %q", name)
+}
+
+// reference returns the interpolated value found at the specified path of
+// the front matter, or of the variables.
+func (ip *interpolator) reference(path string) (interface{}, error) {
+	if val, ok := ip.root.Get(path); ok {
+		return ip.resolve(path, val)
+	}
+	if val, ok := Matter(ip.Vars).Get(path); ok {
+		return val, nil
+	}
+
+	return nil, fmt.Errorf("undefined reference %q", path)
+}
+
+func (ip *interpolator) errorf(path, placeholder string, err error) error {
+	return &InterpolationError{
+		Line: ip.lineOf(path, placeholder),
+		Key:  path,
+		Err:  err,
+	}
+}
+
+// lineOf returns the line of the raw front matter containing the specified
+// placeholder, searching after the last key of the path, if possible.
+func (ip *interpolator) lineOf(path, placeholder string) int {
+	if ip.raw == nil {
+		return 0
+	}
+
+	from := 0
+	key := path[strings.LastIndexByte(path, '.')+1:]
+	if i := bytes.Index(ip.raw, []byte(key)); i >= 0 && key != "" {
+		from = i
+	}
+
+	i := bytes.Index(ip.raw[from:], []byte(placeholder))
+	if i < 0 {
+		if i = bytes.Index(ip.raw, []byte(placeholder)); i < 0 {
+			return 0
+		}
+		from = 0
+	}
+
+	return ip.line + bytes.Count(ip.raw[:from+i], []byte{'\n'})
+}
@@ -0,0 +1,166 @@
+package frontmatter_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestInterpolation(t *testing.T) {
+	type matter struct {
+		Title   string   `yaml:"title"`
+		Version string   `yaml:"version"`
+		URL     string   `yaml:"url"`
+		Tags    []string `yaml:"tags"`
+		Related []string `yaml:"related"`
+		Price   string   `yaml:"price"`
+		Home    string   `yaml:"home"`
+		Weight  int      `yaml:"weight"`
+	}
+
+	interp := &frontmatter.Interpolation{
+		Env: true,
+		LookupEnv: func(key string) (string, bool) {
+			if key == "PRODUCT_VERSION" {
+				return "1.2.3", true
+			}
+			return "", false
+		},
+		Vars: map[string]interface{}{
+			"site": map[string]interface{}{"baseURL": "https://example.com"},
+			"home": "/home",
+		},
+	}
+
+	exp := matter{
+		Title:   "Product 1.2.3",
+		Version: "1.2.3",
+		URL:     "https://example.com/docs/1.2.3/",
+		Tags:    []string{"go", "1.2.3"},
+		Related: []string{"go", "1.2.3"},
+		Price:   "$5",
+		Home:    "/root",
+		Weight:  10,
+	}
+
+	inputs := []string{
+		`---
+title: Product {{ .version }}
+version: ${PRODUCT_VERSION}
+url: "{{ .site.baseURL }}/docs/{{.version}}/"
+tags: [go, "{{ .version }}"]
+related: "{{ .tags }}"
+price: $$5
+home: ${HOME_DIR:-/root}
+weight: "${weight}"
+---
+rest of the file`,
+		`+++
+title = "Product {{ .version }}"
+version = "${PRODUCT_VERSION}"
+url = "{{ .site.baseURL }}/docs/{{.version}}/"
+tags = ["go", "{{ .version }}"]
+related = "{{ .tags }}"
+price = "$$5"
+home = "${HOME_DIR:-/root}"
+weight = "${weight}"
++++
+rest of the file`,
+	}
+
+	interp.Vars["weight"] = 10
+	for _, input := range inputs {
+		var m matter
+		_, err := frontmatter.ParseDocument(strings.NewReader(input), &m,
+			&frontmatter.Options{Interpolation: interp})
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if !reflect.DeepEqual(m, exp) {
+			t.Fatalf("Input: `%s`\n\nexpected %+v, got %+v", input, exp, m)
+		}
+	}
+
+	// The values are not modified without interpolation.
+	raw, _, err := frontmatter.ParseMatter(strings.NewReader(inputs[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version := raw.String("version"); version != "${PRODUCT_VERSION}" {
+		t.Fatalf("unexpected version %q", version)
+	}
+
+	// Interpolate front matters directly.
+	src := frontmatter.Matter{"a": "{{ .b }}", "b": []interface{}{"${home}"}}
+	res, err := interp.Interpolate(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := (frontmatter.Matter{"a": []interface{}{"/home"}, "b": []interface{}{"/home"}}); !reflect.DeepEqual(res, exp) {
+		t.Fatalf("expected %v, got %v", exp, res)
+	}
+	if src["a"] != "{{ .b }}" {
+		t.Fatalf("source modified: %v", src)
+	}
+}
+
+func TestInterpolationErrors(t *testing.T) {
+	testCases := []struct {
+		input string
+		line  int
+		key   string
+		err   string
+	}{
+		{
+			input: "---\ntitle: ok\nversion: ${MISSING}\n---\n",
+			line:  3,
+			key:   "version",
+			err:   `undefined variable "MISSING"`,
+		},
+		{
+			input: "<!-- license -->\n---\ntitle: ok\nauthor:\n  name: \"{{ .missing }}\"\n---\n",
+			line:  5,
+			key:   "author.name",
+			err:   `undefined reference "missing"`,
+		},
+		{
+			input: "---\na: \"{{ .b }}\"\nb: \"x {{ .c }}\"\nc: \"{{ .a }}\"\n---\n",
+			line:  4,
+			key:   "c",
+			err:   "reference cycle a -> b -> c -> a",
+		},
+		{
+			input: "---\nlist: [1, 2]\ntitle: \"list {{ .list }}\"\n---\n",
+			line:  3,
+			key:   "title",
+			err:   "cannot interpolate []interface {} into string",
+		},
+		{
+			input: "---\nauthor:\n  name: \"{{ .author }}\"\n---\n",
+			line:  3,
+			key:   "author.name",
+			err:   "reference cycle author -> author.name -> author",
+		},
+	}
+
+	opts := &frontmatter.Options{
+		Preamble:      &frontmatter.Preamble{Comments: []frontmatter.Comment{{Start: "<!--", End: "-->"}}},
+		Interpolation: &frontmatter.Interpolation{},
+	}
+	for _, tc := range testCases {
+		var m interface{}
+		_, err := frontmatter.ParseDocument(strings.NewReader(tc.input), &m, opts)
+
+		var ierr *frontmatter.InterpolationError
+		if !errors.As(err, &ierr) {
+			t.Fatalf("Input: `%s`\n\nexpected interpolation error, got %v", tc.input, err)
+		}
+		if ierr.Line != tc.line || ierr.Key != tc.key || ierr.Err.Error() != tc.err {
+			t.Fatalf("Input: `%s`\n\nexpected error at line %d (%s): %s, got %v",
+				tc.input, tc.line, tc.key, tc.err, err)
+		}
+	}
+}
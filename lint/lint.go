@@ -0,0 +1,590 @@
+// Package lint checks the style and consistency of front matters.
+//
+// The linter relies on the front matter detected by the parser, along with
+// its raw bytes, in order to report issues using the positions of the
+// front matter in the source files. The keys and values of YAML, TOML and
+// JSON front matters are inspected line by line, without decoding them, so
+// issues hidden by the decoders (e.g. duplicate keys) are reported as well,
+// even if the front matter cannot be decoded.
+// E.g.:
+//
+//	issues, err := lint.Lint(os.DirFS("content"), ".", &lint.Config{
+//		Rules: []lint.Rule{lint.RuleDelimiter, lint.RuleSortedKeys},
+//	})
+//	if err != nil {
+//		// Treat error.
+//	}
+//
+//	for _, issue := range issues {
+//		fmt.Println(issue) // E.g.: post.md:3:1: key "date" should be sorted before "title" (sorted-keys)
+//	}
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Rule identifies a lint rule.
+type Rule string
+
+// Lint rules.
+const (
+	// RuleParse reports front matters which cannot be decoded.
+	// It cannot be disabled.
+	RuleParse Rule = "parse"
+
+	// RuleDelimiter reports documents whose front matter delimiter differs
+	// from the configured one or, if none is configured, from the one used
+	// by most documents.
+	RuleDelimiter Rule = "delimiter"
+
+	// RuleDelimiterWhitespace reports delimiter lines with trailing
+	// whitespace.
+	RuleDelimiterWhitespace Rule = "delimiter-whitespace"
+
+	// RuleEmpty reports front matter blocks without values.
+	RuleEmpty Rule = "empty"
+
+	// RuleSortedKeys reports top-level keys which are not sorted.
+	// For TOML front matters, the keys of each table are checked.
+	RuleSortedKeys Rule = "sorted-keys"
+
+	// RuleDuplicateKeys reports top-level keys which are defined more
+	// than once. For TOML front matters, the keys of each table are checked.
+	RuleDuplicateKeys Rule = "duplicate-keys"
+
+	// RuleQuoting reports YAML and TOML strings whose quotes differ from
+	// the configured style or, if none is configured, from the first quoted
+	// string of the front matter.
+	RuleQuoting Rule = "quoting"
+)
+
+// Rules contains the rules enabled by default.
+var Rules = []Rule{
+	RuleDelimiter,
+	RuleDelimiterWhitespace,
+	RuleEmpty,
+	RuleSortedKeys,
+	RuleDuplicateKeys,
+	RuleQuoting,
+}
+
+// Description returns a short description of the rule.
+func (r Rule) Description() string {
+	switch r {
+	case RuleParse:
+		return "Front matter must be valid."
+	case RuleDelimiter:
+		return "Documents must use the same front matter delimiter."
+	case RuleDelimiterWhitespace:
+		return "Delimiter lines must not contain trailing whitespace."
+	case RuleEmpty:
+		return "Front matter blocks must not be empty."
+	case RuleSortedKeys:
+		return "Front matter keys must be sorted."
+	case RuleDuplicateKeys:
+		return "Front matter keys must be unique."
+	case RuleQuoting:
+		return "Strings must use the same quote style."
+	}
+
+	return ""
+}
+
+// Quote styles.
+const (
+	QuoteDouble = "double"
+	QuoteSingle = "single"
+)
+
+// Config holds the configuration of the linter.
+type Config struct {
+	// Rules defines the enabled rules. If no rules are provided, the
+	// `Rules` are used. The `RuleParse` rule is always enabled.
+	Rules []Rule
+
+	// Delimiter defines the expected start delimiter of the front matters
+	// (e.g. `---` or `+++`), or the line prefix of header front matters.
+	// If empty, the delimiter used by most documents is expected.
+	Delimiter string
+
+	// Quote defines the expected quote style of strings: `double` or
+	// `single`. If empty, the style of the first quoted string of each
+	// front matter is expected.
+	Quote string
+
+	// Pattern defines the pattern matched against the names of the
+	// documents, using the syntax of `path.Match`. If empty, `*.md` is used.
+	Pattern string
+
+	// Options holds the configuration used to parse the documents.
+	// Front matters located at the end of the documents are not supported.
+	Options *frontmatter.Options
+}
+
+func (c *Config) enabled(rule Rule) bool {
+	if rule == RuleParse || len(c.Rules) == 0 {
+		return true
+	}
+	for _, r := range c.Rules {
+		if r == rule {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Issue describes a problem found by the linter.
+type Issue struct {
+	// Path is the slash-separated path of the document.
+	Path string `json:"path"`
+
+	// Line is the line of the issue, starting at 1.
+	Line int `json:"line"`
+
+	// Column is the byte column of the issue, starting at 1.
+	Column int `json:"column"`
+
+	// Rule is the rule which reported the issue.
+	Rule Rule `json:"rule"`
+
+	// Message describes the issue.
+	Message string `json:"message"`
+}
+
+// String returns the issue in the `path:line:column: message (rule)` format.
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s (%s)", i.Path, i.Line, i.Column, i.Message, i.Rule)
+}
+
+// Lint checks the documents found in the specified directory and its
+// subdirectories, and returns the issues sorted by path and position.
+// An error is returned only if the documents cannot be read.
+func Lint(fsys fs.FS, root string, cfg *Config) ([]Issue, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	var docs []*document
+	err := frontmatter.Walk(fsys, root, cfg.Pattern, func(name string, d fs.DirEntry) error {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		docs = append(docs, newDocument(name, data, cfg))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delim := cfg.Delimiter
+	if delim == "" {
+		delim = commonDelimiter(docs)
+	}
+
+	var issues []Issue
+	for _, doc := range docs {
+		issues = append(issues, doc.lint(cfg, delim)...)
+	}
+	sortIssues(issues)
+
+	return issues, nil
+}
+
+// LintFile checks the specified document data, and returns the issues
+// sorted by position. The delimiter of the document is checked only if
+// the configuration specifies the expected delimiter.
+func LintFile(name string, data []byte, cfg *Config) []Issue {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+
+	issues := newDocument(name, data, cfg).lint(cfg, cfg.Delimiter)
+	sortIssues(issues)
+
+	return issues
+}
+
+// document holds a parsed document and the position of its front matter.
+type document struct {
+	name   string
+	doc    *frontmatter.Document
+	value  interface{}
+	err    error
+	line   int
+	lines  []string
+	indent []int
+	issues []Issue
+}
+
+func newDocument(name string, data []byte, cfg *Config) *document {
+	d := &document{name: name, line: 1}
+
+	var opts frontmatter.Options
+	if cfg.Options != nil {
+		opts = *cfg.Options
+	}
+	opts.Tail = false
+
+	d.doc, d.err = frontmatter.ParseDocument(bytes.NewReader(data), &d.value, &opts)
+	if d.err != nil {
+		// Detect the front matter without decoding it, so that its lines
+		// are checked even if it cannot be decoded.
+		d.doc = detect(data, opts)
+	}
+	if d.doc == nil || d.doc.Format == nil {
+		return d
+	}
+
+	d.line += bytes.Count(d.doc.Preamble, []byte{'\n'})
+	for _, line := range strings.SplitAfter(string(d.doc.Matter), "\n") {
+		if line == "" {
+			continue
+		}
+
+		line = strings.TrimSuffix(line, "\n")
+		line, indent := stripPrefix(strings.TrimSuffix(line, "\r"), d.doc.Format.Prefix)
+		d.lines, d.indent = append(d.lines, line), append(d.indent, indent)
+	}
+
+	return d
+}
+
+// stripPrefix removes the specified line prefix, along with the whitespace
+// preceding it and a single space or tab following it, from the specified
+// line, in the same way as the parser. It also returns the number of
+// removed bytes.
+func stripPrefix(line, prefix string) (string, int) {
+	if prefix == "" {
+		return line, 0
+	}
+
+	trimmed := strings.TrimLeft(line, " \t")
+	if !strings.HasPrefix(trimmed, prefix) {
+		return line, 0
+	}
+	trimmed = trimmed[len(prefix):]
+	if len(trimmed) > 0 && (trimmed[0] == ' ' || trimmed[0] == '\t') {
+		trimmed = trimmed[1:]
+	}
+
+	return trimmed, len(line) - len(trimmed)
+}
+
+// detect returns the document containing the front matter of the specified
+// data, which is detected using copies of the formats which do not decode
+// the front matter. It returns nil if the data cannot be read.
+func detect(data []byte, opts frontmatter.Options) *frontmatter.Document {
+	formats := opts.Formats
+	if len(formats) == 0 {
+		formats = defaultFormats()
+		if opts.HTMLComments {
+			formats = append(formats, frontmatter.HTMLFormats()...)
+		}
+	}
+
+	opts.Formats = make([]*frontmatter.Format, len(formats))
+	for i, f := range formats {
+		c := *f
+		c.Unmarshal = func([]byte, interface{}) error { return nil }
+		opts.Formats[i] = &c
+	}
+	opts.DecodeHooks, opts.Includes, opts.Interpolation = nil, nil, nil
+
+	var v interface{}
+	doc, err := frontmatter.ParseDocument(bytes.NewReader(data), &v, &opts)
+	if err != nil {
+		return nil
+	}
+
+	return doc
+}
+
+// defaultFormats returns formats identifying the front matters in the same
+// way as the default formats of the parser, used to detect the front
+// matters which cannot be decoded.
+func defaultFormats() []*frontmatter.Format {
+	return []*frontmatter.Format{
+		frontmatter.NewFormat("---", "---", nil),
+		frontmatter.NewFormat("---yaml", "---", nil),
+		frontmatter.NewFormat("+++", "+++", nil),
+		frontmatter.NewFormat("---toml", "---", nil),
+		frontmatter.NewFormat(";;;", ";;;", nil),
+		frontmatter.NewFormat("---json", "---", nil),
+		{
+			Start:           "{",
+			End:             "}",
+			UnmarshalDelims: true,
+			RequiresNewLine: true,
+			Scan:            scanObject,
+		},
+	}
+}
+
+// scanObject returns the length of the JSON object at the beginning of the
+// specified data.
+func scanObject(data []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return 0, err
+	}
+	if len(raw) == 0 || raw[0] != '{' {
+		return 0, errors.New("not a JSON object")
+	}
+
+	return int(dec.InputOffset()), nil
+}
+
+// delimiter returns the start delimiter of the front matter, or its line
+// prefix for header front matters.
+func (d *document) delimiter() string {
+	if d.doc == nil || d.doc.Format == nil {
+		return ""
+	}
+	f := d.doc.Format
+	if f.Start != "" {
+		return f.Start
+	}
+
+	return f.Prefix
+}
+
+// column returns the column of the document which corresponds to the
+// specified column of a front matter line, whose prefix was removed.
+func (d *document) column(line, column int) int {
+	if i := line - d.line; i >= 0 && i < len(d.indent) {
+		column += d.indent[i]
+	}
+
+	return column
+}
+
+func (d *document) report(line, column int, rule Rule, format string, args ...interface{}) {
+	d.issues = append(d.issues, Issue{
+		Path:    d.name,
+		Line:    line,
+		Column:  column,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (d *document) lint(cfg *Config, delim string) []Issue {
+	d.issues = nil
+	if d.err != nil {
+		d.report(d.line, 1, RuleParse, "invalid front matter: %v", d.err)
+	}
+	if d.doc == nil || d.doc.Format == nil {
+		return d.issues
+	}
+
+	if cfg.enabled(RuleDelimiter) && delim != "" && d.delimiter() != delim {
+		d.report(d.line, 1, RuleDelimiter, "delimiter %q should be %q", d.delimiter(), delim)
+	}
+	if cfg.enabled(RuleDelimiterWhitespace) {
+		d.checkDelimiterWhitespace()
+	}
+	if cfg.enabled(RuleEmpty) && d.err == nil && isEmpty(d.value) {
+		d.report(d.line, 1, RuleEmpty, "empty front matter block")
+	}
+
+	content, first := d.content()
+	if len(content) == 0 {
+		return d.issues
+	}
+
+	var keys []key
+	var quotes []quote
+	switch d.family() {
+	case "yaml":
+		keys, quotes = scanYAML(content)
+	case "toml":
+		keys, quotes = scanTOML(content)
+	case "json":
+		keys = scanJSON(content)
+	}
+
+	if cfg.enabled(RuleDuplicateKeys) {
+		d.checkDuplicateKeys(keys, first)
+	}
+	if cfg.enabled(RuleSortedKeys) {
+		d.checkSortedKeys(keys, first)
+	}
+	if cfg.enabled(RuleQuoting) {
+		d.checkQuoting(quotes, first, cfg.Quote)
+	}
+
+	return d.issues
+}
+
+// content returns the lines of the front matter, excluding the delimiters,
+// along with the line number of the first one.
+func (d *document) content() ([]string, int) {
+	f := d.doc.Format
+	if f.Start == "" || (f.UnmarshalDelims && f.Scan != nil) {
+		// Header front matters and JSON objects do not have delimiter lines.
+		return d.lines, d.line
+	}
+	if len(d.lines) < 2 {
+		// Inline front matter.
+		return nil, d.line
+	}
+
+	return d.lines[1 : len(d.lines)-1], d.line + 1
+}
+
+// family returns the language of the front matter, based on its delimiter.
+func (d *document) family() string {
+	f := d.doc.Format
+	if f.Start == "" {
+		return ""
+	}
+
+	switch f.Start {
+	case "+++", "---toml":
+		return "toml"
+	case ";;;", "---json", "<!--json", "{":
+		return "json"
+	case "---", "---yaml", "<!--", "<!--yaml":
+		return "yaml"
+	}
+
+	return ""
+}
+
+func (d *document) checkDelimiterWhitespace() {
+	f := d.doc.Format
+	if len(d.lines) == 0 || f.Start == "" {
+		return
+	}
+
+	check := func(i int) {
+		line := d.lines[i]
+		if trimmed := strings.TrimRight(line, " \t"); len(trimmed) != len(line) {
+			d.report(d.line+i, d.column(d.line+i, len(trimmed)+1), RuleDelimiterWhitespace, "trailing whitespace after delimiter")
+		}
+	}
+
+	check(0)
+	if last := len(d.lines) - 1; last > 0 {
+		check(last)
+	}
+}
+
+func (d *document) checkDuplicateKeys(keys []key, first int) {
+	seen := map[string]int{}
+	section := -1
+	for _, k := range keys {
+		if k.section != section {
+			seen, section = map[string]int{}, k.section
+		}
+
+		if line, ok := seen[k.name]; ok {
+			d.report(first+k.line, d.column(first+k.line, k.column), RuleDuplicateKeys,
+				"duplicate key %q, first defined on line %d", k.name, first+line)
+			continue
+		}
+		seen[k.name] = k.line
+	}
+}
+
+func (d *document) checkSortedKeys(keys []key, first int) {
+	for i := 1; i < len(keys); i++ {
+		prev, k := keys[i-1], keys[i]
+		if k.section == prev.section && k.name < prev.name {
+			d.report(first+k.line, d.column(first+k.line, k.column), RuleSortedKeys,
+				"key %q should be sorted before %q", k.name, prev.name)
+		}
+	}
+}
+
+func (d *document) checkQuoting(quotes []quote, first int, style string) {
+	var expected byte
+	switch style {
+	case QuoteDouble:
+		expected = '"'
+	case QuoteSingle:
+		expected = '\''
+	}
+
+	for _, q := range quotes {
+		if expected == 0 {
+			expected = q.char
+			continue
+		}
+		if q.char != expected {
+			d.report(first+q.line, d.column(first+q.line, q.column), RuleQuoting,
+				"string uses %s quotes, expected %s quotes", quoteStyle(q.char), quoteStyle(expected))
+		}
+	}
+}
+
+func quoteStyle(c byte) string {
+	if c == '\'' {
+		return QuoteSingle
+	}
+
+	return QuoteDouble
+}
+
+func isEmpty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		return len(t) == 0
+	case map[interface{}]interface{}:
+		return len(t) == 0
+	}
+
+	return false
+}
+
+// commonDelimiter returns the delimiter used by most documents. Ties are
+// broken in favor of the delimiter of the first document.
+func commonDelimiter(docs []*document) string {
+	counts := map[string]int{}
+	var delim string
+	for _, doc := range docs {
+		d := doc.delimiter()
+		if d == "" {
+			continue
+		}
+
+		counts[d]++
+		if counts[d] > counts[delim] {
+			delim = d
+		}
+	}
+
+	return delim
+}
+
+func sortIssues(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Rule < b.Rule
+	})
+}
@@ -0,0 +1,259 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/lint"
+)
+
+func TestLintFile(t *testing.T) {
+	testCases := []struct {
+		input  string
+		cfg    *lint.Config
+		issues []string
+	}{
+		// Valid front matters.
+		{
+			input: "---\nauthor: John\ntags:\n  - go\n  - yaml\ntitle: 'It''s'\n---\nbody",
+		},
+		{
+			input: "+++\nauthor = \"John\"\ntitle = \"Post\"\n\n[params]\nb = 1\nc = \"\"\"\nz = 1\n\"\"\"\n+++\n",
+		},
+		{
+			input: "{\n  \"a\": {\"z\": 1, \"b\": 2},\n  \"b\": [\"x\", \"y\"]\n}\n\nbody",
+		},
+		{
+			input: "no front matter",
+		},
+		// Sorted and duplicate keys.
+		{
+			input: "preamble\n---\ntitle: Post\nauthor:\n  name: John\n  age: 30\ntitle: Other\n---\n",
+			cfg: &lint.Config{Options: &frontmatter.Options{
+				Preamble: &frontmatter.Preamble{Lines: 1},
+			}},
+			issues: []string{
+				"doc.md:4:1: key \"author\" should be sorted before \"title\" (sorted-keys)",
+				"doc.md:7:1: duplicate key \"title\", first defined on line 3 (duplicate-keys)",
+			},
+		},
+		{
+			input: "+++\nb = 1\na = 2\n[t]\nz = 1\ny = 2\n[u]\nb = 3\n+++\n",
+			issues: []string{
+				"doc.md:3:1: key \"a\" should be sorted before \"b\" (sorted-keys)",
+				"doc.md:6:1: key \"y\" should be sorted before \"z\" (sorted-keys)",
+			},
+		},
+		{
+			input: ";;;\n{\"b\": 1,\n \"a\": {\"c\": 2}, \"a\": 3}\n;;;\n",
+			issues: []string{
+				"doc.md:3:2: key \"a\" should be sorted before \"b\" (sorted-keys)",
+				"doc.md:3:17: duplicate key \"a\", first defined on line 3 (duplicate-keys)",
+			},
+		},
+		// Delimiter whitespace and empty blocks.
+		{
+			input: "---  \ntitle: Post\n---\t\nbody",
+			issues: []string{
+				"doc.md:1:4: trailing whitespace after delimiter (delimiter-whitespace)",
+				"doc.md:3:4: trailing whitespace after delimiter (delimiter-whitespace)",
+			},
+		},
+		{
+			input:  "---\n\n---\nbody",
+			issues: []string{"doc.md:1:1: empty front matter block (empty)"},
+		},
+		{
+			input:  "{}\n\nbody",
+			issues: []string{"doc.md:1:1: empty front matter block (empty)"},
+		},
+		// Quoting.
+		{
+			input: "---\na: \"x\"\nb: 'y'\nc: [\"z\", 'w']\nd: Don't\ne: |\n  'quoted'\n---\n",
+			issues: []string{
+				"doc.md:3:4: string uses single quotes, expected double quotes (quoting)",
+				"doc.md:4:10: string uses single quotes, expected double quotes (quoting)",
+			},
+		},
+		{
+			input: "+++\na = \"x\"\nb = ['y', \"z\"]\n+++\n",
+			cfg:   &lint.Config{Quote: lint.QuoteSingle},
+			issues: []string{
+				"doc.md:2:5: string uses double quotes, expected single quotes (quoting)",
+				"doc.md:3:11: string uses double quotes, expected single quotes (quoting)",
+			},
+		},
+		// Configuration.
+		{
+			input:  "+++\nb = 1\na = 2\n+++\n",
+			cfg:    &lint.Config{Delimiter: "---"},
+			issues: []string{"doc.md:1:1: delimiter \"+++\" should be \"---\" (delimiter)", "doc.md:3:1: key \"a\" should be sorted before \"b\" (sorted-keys)"},
+		},
+		{
+			input:  "+++\nb = 1\na = 2\n+++\n",
+			cfg:    &lint.Config{Delimiter: "---", Rules: []lint.Rule{lint.RuleDuplicateKeys}},
+			issues: nil,
+		},
+		{
+			input:  "---\ntitle: [\n---\n",
+			cfg:    &lint.Config{Rules: []lint.Rule{lint.RuleEmpty}},
+			issues: []string{"doc.md:1:1: invalid front matter: yaml: line 1: did not find expected node content (parse)"},
+		},
+		// Comment front matters.
+		{
+			input: "// ---\n// title: Post\n//author: 'John'\n  // --- \npackage main",
+			cfg:   &lint.Config{Options: &frontmatter.Options{Formats: frontmatter.CommentFormats("//")}},
+			issues: []string{
+				"doc.md:3:3: key \"author\" should be sorted before \"title\" (sorted-keys)",
+				"doc.md:4:9: trailing whitespace after delimiter (delimiter-whitespace)",
+			},
+		},
+		{
+			input: "# +++\n# b = 1\n# b = 2\n# +++\n",
+			cfg:   &lint.Config{Options: &frontmatter.Options{Formats: frontmatter.CommentFormats("#")}},
+			issues: []string{
+				"doc.md:1:1: invalid front matter: toml: line 2 (last key \"b\"): Key 'b' has already been defined. (parse)",
+				"doc.md:3:3: duplicate key \"b\", first defined on line 2 (duplicate-keys)",
+			},
+		},
+		// Front matters which cannot be decoded.
+		{
+			input: "+++\ntitle = 'a'\ntitle = 'b'\n+++\n",
+			issues: []string{
+				"doc.md:1:1: invalid front matter: toml: line 2 (last key \"title\"): Key 'title' has already been defined. (parse)",
+				"doc.md:3:1: duplicate key \"title\", first defined on line 2 (duplicate-keys)",
+			},
+		},
+		{
+			input: "{\n\"b\": 1,\n\"b\": 2,\n}\n\nbody",
+			issues: []string{
+				"doc.md:1:1: invalid front matter: invalid character '}' looking for beginning of object key string (parse)",
+				"doc.md:3:1: duplicate key \"b\", first defined on line 2 (duplicate-keys)",
+			},
+		},
+		{
+			input: "preamble\n\n---\ntitle: [\nauthor: 'John'\n---\n",
+			cfg: &lint.Config{Options: &frontmatter.Options{
+				Preamble: &frontmatter.Preamble{Lines: 2},
+			}},
+			issues: []string{
+				"doc.md:3:1: invalid front matter: yaml: line 2: did not find expected ',' or ']' (parse)",
+				"doc.md:5:1: key \"author\" should be sorted before \"title\" (sorted-keys)",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		var issues []string
+		for _, issue := range lint.LintFile("doc.md", []byte(tc.input), tc.cfg) {
+			issues = append(issues, issue.String())
+		}
+
+		if !reflect.DeepEqual(issues, tc.issues) {
+			t.Fatalf("Input: `%s`\n\nexpected issues:\n%q\ngot:\n%q", tc.input, tc.issues, issues)
+		}
+	}
+}
+
+func TestLint(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.md":      {Data: []byte("+++\ntitle = \"A\"\n+++\n")},
+		"b.md":      {Data: []byte("---\ntitle: B\n---\n")},
+		"blog/c.md": {Data: []byte("---\ntitle: C\n---\n")},
+		"blog/d.md": {Data: []byte("no front matter")},
+		"e.txt":     {Data: []byte("+++\nb = 1\na = 2\n+++\n")},
+	}
+
+	issues, err := lint.Lint(fsys, ".", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []lint.Issue{
+		{Path: "a.md", Line: 1, Column: 1, Rule: lint.RuleDelimiter, Message: `delimiter "+++" should be "---"`},
+	}
+	if !reflect.DeepEqual(issues, expected) {
+		t.Fatalf("expected %v, got %v", expected, issues)
+	}
+
+	// Ties are broken in favor of the first document.
+	if issues, err = lint.Lint(fsys, ".", &lint.Config{Pattern: "[ab].*"}); err != nil || len(issues) != 1 || issues[0].Path != "b.md" {
+		t.Fatalf("unexpected issues: %v %v", issues, err)
+	}
+
+	if _, err := lint.Lint(fsys, "missing", nil); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
+
+func TestReports(t *testing.T) {
+	issues := []lint.Issue{
+		{Path: "a.md", Line: 3, Column: 2, Rule: lint.RuleSortedKeys, Message: `key "a" should be sorted before "b"`},
+	}
+
+	var buf bytes.Buffer
+	if err := lint.WriteJSON(&buf, nil); err != nil || buf.String() != "[]\n" {
+		t.Fatalf("unexpected output: %q %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := lint.WriteJSON(&buf, issues); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var decoded []lint.Issue
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || !reflect.DeepEqual(decoded, issues) {
+		t.Fatalf("unexpected output: %s %v", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := lint.WriteSARIF(&buf, issues); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region struct {
+							StartLine   int `json:"startLine"`
+							StartColumn int `json:"startColumn"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Tool.Driver.Rules) != len(lint.Rules)+1 {
+		t.Fatalf("unexpected SARIF log: %s", buf.String())
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 1 || results[0].RuleID != "sorted-keys" || len(results[0].Locations) != 1 {
+		t.Fatalf("unexpected SARIF results: %s", buf.String())
+	}
+	if loc := results[0].Locations[0].PhysicalLocation; loc.ArtifactLocation.URI != "a.md" ||
+		loc.Region.StartLine != 3 || loc.Region.StartColumn != 2 {
+		t.Fatalf("unexpected SARIF location: %+v", loc)
+	}
+}
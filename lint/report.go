@@ -0,0 +1,101 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// WriteJSON writes the specified issues to w, as an indented JSON array.
+func WriteJSON(w io.Writer, issues []Issue) error {
+	if issues == nil {
+		issues = []Issue{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// sarifSchema is the JSON schema of the SARIF 2.1.0 format.
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool struct {
+		Driver struct {
+			Name  string      `json:"name"`
+			Rules []sarifRule `json:"rules"`
+		} `json:"driver"`
+	} `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine   int `json:"startLine"`
+			StartColumn int `json:"startColumn"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+// WriteSARIF writes the specified issues to w, as a SARIF 2.1.0 log
+// containing a single run. All issues are reported with the `error` level,
+// and their paths are used as artifact URIs.
+func WriteSARIF(w io.Writer, issues []Issue) error {
+	var run sarifRun
+	run.Tool.Driver.Name = "frontmatter-lint"
+	for _, rule := range append([]Rule{RuleParse}, Rules...) {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:               string(rule),
+			ShortDescription: sarifMessage{Text: rule.Description()},
+		})
+	}
+
+	run.Results = []sarifResult{}
+	for _, issue := range issues {
+		var loc sarifLocation
+		loc.PhysicalLocation.ArtifactLocation.URI = issue.Path
+		loc.PhysicalLocation.Region.StartLine = issue.Line
+		loc.PhysicalLocation.Region.StartColumn = issue.Column
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    string(issue.Rule),
+			Level:     "error",
+			Message:   sarifMessage{Text: issue.Message},
+			Locations: []sarifLocation{loc},
+		})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,344 @@
+package lint
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// key is a front matter key found by the scanners. Lines are relative
+// to the first line of the content, and columns start at 1.
+type key struct {
+	name    string
+	line    int
+	column  int
+	section int
+}
+
+// quote is the start of a quoted string found by the scanners.
+type quote struct {
+	char   byte
+	line   int
+	column int
+}
+
+// scanYAML returns the top-level keys and the quoted strings of the
+// specified YAML lines.
+func scanYAML(lines []string) ([]key, []quote) {
+	var keys []key
+	var quotes []quote
+
+	block := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		// Skip the lines of block scalars.
+		if block >= 0 {
+			if trimmed == "" || indent > block {
+				continue
+			}
+			block = -1
+		}
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+
+		rest, col := line[indent:], indent
+		item := false
+		for strings.HasPrefix(rest, "- ") || rest == "-" {
+			n := len(rest) - len(strings.TrimLeft(rest[1:], " \t"))
+			rest, col, item = rest[n:], col+n, true
+		}
+
+		name, n, ok := yamlKey(rest)
+		if !ok {
+			quotes = appendQuotes(quotes, rest, i, col)
+			continue
+		}
+		if indent == 0 && !item {
+			keys = append(keys, key{name: name, line: i, column: 1})
+		}
+
+		value := strings.TrimSpace(rest[n:])
+		if value != "" && (value[0] == '|' || value[0] == '>') {
+			block = indent
+			continue
+		}
+		quotes = appendQuotes(quotes, rest[n:], i, col+n)
+	}
+
+	return keys, quotes
+}
+
+// yamlKey returns the key at the beginning of the specified line, along
+// with the length of the key and of the `:` separator, and reports whether
+// the line contains a key.
+func yamlKey(line string) (string, int, bool) {
+	if line == "" {
+		return "", 0, false
+	}
+
+	var name string
+	i := 0
+	switch c := line[0]; c {
+	case '"', '\'':
+		end := closingQuote(line, c)
+		if end < 0 {
+			return "", 0, false
+		}
+		name = unquote(line[:end+1])
+		i = end + 1 + len(line[end+1:]) - len(strings.TrimLeft(line[end+1:], " \t"))
+		if i >= len(line) || line[i] != ':' {
+			return "", 0, false
+		}
+	case '[', '{', '#', '&', '*', '!', '|', '>', '%', '@', '`':
+		return "", 0, false
+	default:
+		for ; i < len(line) && line[i] != ':'; i++ {
+			if line[i] == '#' && (line[i-1] == ' ' || line[i-1] == '\t') {
+				return "", 0, false
+			}
+		}
+		for i < len(line) && i+1 < len(line) && line[i+1] != ' ' && line[i+1] != '\t' {
+			// Colons which are not followed by whitespace are part of the key.
+			j := strings.IndexByte(line[i+1:], ':')
+			if j < 0 {
+				return "", 0, false
+			}
+			i += j + 1
+		}
+		if i >= len(line) {
+			return "", 0, false
+		}
+		if name = strings.TrimSpace(line[:i]); name == "" {
+			return "", 0, false
+		}
+	}
+
+	if i+1 < len(line) && line[i+1] != ' ' && line[i+1] != '\t' {
+		return "", 0, false
+	}
+
+	return name, i + 1, true
+}
+
+// tableHeader matches the TOML table and array of tables headers.
+var tableHeader = regexp.MustCompile(`^\[\[?\s*[\w."' -]+\s*\]\]?\s*(#.*)?$`)
+
+// scanTOML returns the keys and the quoted strings of the specified TOML
+// lines. The keys of each table have a different section.
+func scanTOML(lines []string) ([]key, []quote) {
+	var keys []key
+	var quotes []quote
+
+	section := 0
+	multi := ""
+	for i, line := range lines {
+		if multi != "" {
+			if strings.Contains(line, multi) {
+				multi = ""
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed[0] == '#' {
+			continue
+		}
+		if tableHeader.MatchString(trimmed) {
+			section++
+			continue
+		}
+
+		col := len(line) - len(strings.TrimLeft(line, " \t"))
+		rest := line[col:]
+		if name, n, ok := tomlKey(rest); ok {
+			keys = append(keys, key{name: name, line: i, column: col + 1, section: section})
+			rest, col = rest[n:], col+n
+		}
+
+		quotes = appendQuotes(quotes, rest, i, col)
+		if q := openMultiline(rest); q != "" {
+			multi = q
+		}
+	}
+
+	return keys, quotes
+}
+
+// tomlKey returns the key at the beginning of the specified line, along
+// with the length of the key and of the `=` separator, and reports whether
+// the line contains a key.
+func tomlKey(line string) (string, int, bool) {
+	var name string
+	n := 0
+	for n < len(line) {
+		switch c := line[n]; {
+		case c == '"' || c == '\'':
+			end := closingQuote(line[n:], c)
+			if end < 0 {
+				return "", 0, false
+			}
+			name += unquote(line[n : n+end+1])
+			n += end + 1
+		case c == '.' || c == '_' || c == '-' || isAlnum(c):
+			name += string(c)
+			n++
+		case c == ' ' || c == '\t':
+			n++
+		case c == '=' && name != "":
+			return name, n + 1, true
+		default:
+			return "", 0, false
+		}
+	}
+
+	return "", 0, false
+}
+
+// openMultiline returns the delimiter of the multi-line string which is
+// opened and not closed on the specified line, if any.
+func openMultiline(line string) string {
+	for _, q := range []string{`"""`, `'''`} {
+		if i := strings.Index(line, q); i >= 0 && !strings.Contains(line[i+3:], q) {
+			return q
+		}
+	}
+
+	return ""
+}
+
+// scanJSON returns the top-level keys of the specified JSON lines.
+func scanJSON(lines []string) []key {
+	data := strings.Join(lines, "\n")
+	dec := json.NewDecoder(strings.NewReader(data))
+
+	var keys []key
+	depth, expectKey := 0, false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return keys
+		}
+		if depth == 0 && tok != json.Delim('{') {
+			return keys
+		}
+
+		switch t := tok.(type) {
+		case json.Delim:
+			switch t {
+			case '{', '[':
+				depth++
+				expectKey = depth == 1 && t == '{'
+			case '}', ']':
+				depth--
+				expectKey = depth == 1
+			}
+			continue
+		case string:
+			if depth == 1 && expectKey {
+				offset := int(dec.InputOffset())
+				start := openingQuote(data, offset-1)
+
+				line := strings.Count(data[:start], "\n")
+				column := start - strings.LastIndex(data[:start], "\n")
+				keys = append(keys, key{name: t, line: line, column: column})
+
+				expectKey = false
+				continue
+			}
+		}
+		if depth == 1 {
+			expectKey = true
+		}
+	}
+}
+
+// appendQuotes appends the quoted strings found in the specified value to
+// quotes. Quotes are considered only at the beginning of the value, or after
+// the flow separators (`[`, `{`, `,`, `:` and `=`), so apostrophes inside
+// plain strings are ignored.
+func appendQuotes(quotes []quote, value string, line, col int) []quote {
+	start := true
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\'':
+			if !start {
+				continue
+			}
+			quotes = append(quotes, quote{char: c, line: line, column: col + i + 1})
+
+			end := closingQuote(value[i:], c)
+			if end < 0 {
+				return quotes
+			}
+			i += end
+			start = false
+		case '#':
+			if i == 0 || value[i-1] == ' ' || value[i-1] == '\t' {
+				return quotes
+			}
+			start = false
+		case '[', '{', ',', ':', '=':
+			start = true
+		case ' ', '\t':
+		default:
+			start = false
+		}
+	}
+
+	return quotes
+}
+
+// closingQuote returns the index of the quote closing the string at the
+// beginning of s, or -1 if the string is not closed.
+func closingQuote(s string, c byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case c == '"' && s[i] == '\\':
+			i++
+		case s[i] == c:
+			if c == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				// Escaped single quote.
+				i++
+				continue
+			}
+			return i
+		}
+	}
+
+	return -1
+}
+
+// openingQuote returns the index of the quote opening the JSON string which
+// is closed by the quote at the specified index.
+func openingQuote(s string, end int) int {
+	for i := end - 1; i >= 0; i-- {
+		if s[i] != '"' {
+			continue
+		}
+
+		escapes := 0
+		for j := i - 1; j >= 0 && s[j] == '\\'; j-- {
+			escapes++
+		}
+		if escapes%2 == 0 {
+			return i
+		}
+	}
+
+	return 0
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 {
+		s = s[1 : len(s)-1]
+	}
+
+	return s
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
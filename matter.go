@@ -0,0 +1,242 @@
+package frontmatter
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Matter holds a front matter decoded into a generic map. Its accessors
+// retrieve values using dotted paths (e.g. `author.name` or `tags.0`) and
+// convert them to the requested type, regardless of the format used to
+// decode the front matter. The accessors return the zero value of the type
+// if the value is not found or cannot be converted.
+type Matter map[string]interface{}
+
+// ParseMatter decodes the front matter from the specified reader into
+// a generic map, and returns it along with the rest of the data. If a front
+// matter is not present, an empty map and the original data are returned.
+// Front matters are detected and decoded based on the passed in `formats`.
+// If no formats are provided, the default formats are used.
+func ParseMatter(r io.Reader, formats ...*Format) (Matter, []byte, error) {
+	var v interface{}
+	rest, err := Parse(r, &v, formats...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m, err := newMatter(v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return m, rest, nil
+}
+
+// newMatter converts the specified decoded front matter into a Matter.
+func newMatter(v interface{}) (Matter, error) {
+	switch t := normalize(v).(type) {
+	case nil:
+		return Matter{}, nil
+	case map[string]interface{}:
+		return Matter(t), nil
+	case Matter:
+		return t, nil
+	default:
+		return nil, fmt.Errorf("cannot use %T as front matter", v)
+	}
+}
+
+// Get returns the value found at the specified path, and reports whether
+// the value exists. Path segments are separated by dots, and list elements
+// are referenced by their index. Keys containing dots are matched as well.
+func (m Matter) Get(path string) (interface{}, bool) {
+	var cur interface{} = map[string]interface{}(m)
+	for path != "" {
+		key, rest, _ := strings.Cut(path, ".")
+
+		switch t := cur.(type) {
+		case map[string]interface{}:
+			// Prefer keys containing dots.
+			if val, ok := t[path]; ok {
+				return val, true
+			}
+
+			val, ok := t[key]
+			if !ok {
+				return nil, false
+			}
+			cur, path = val, rest
+		case map[interface{}]interface{}:
+			if val, ok := t[path]; ok {
+				return val, true
+			}
+
+			val, ok := t[key]
+			if !ok {
+				return nil, false
+			}
+			cur, path = val, rest
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, false
+			}
+			cur, path = t[i], rest
+		default:
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+// Has reports whether a value exists at the specified path.
+func (m Matter) Has(path string) bool {
+	_, ok := m.Get(path)
+	return ok
+}
+
+// Keys returns the sorted keys of the front matter.
+func (m Matter) Keys() []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Map returns the nested front matter found at the specified path.
+func (m Matter) Map(path string) Matter {
+	val, _ := m.Get(path)
+	switch t := val.(type) {
+	case map[string]interface{}:
+		return Matter(t)
+	case map[interface{}]interface{}:
+		return Matter(normalize(t).(map[string]interface{}))
+	}
+
+	return nil
+}
+
+// String returns the value found at the specified path as a string.
+// Numbers, booleans and times are formatted.
+func (m Matter) String(path string) string {
+	val, _ := m.Get(path)
+	s, _ := toString(val)
+	return s
+}
+
+// Strings returns the value found at the specified path as a list of
+// strings. Single values are returned as a list containing one element.
+func (m Matter) Strings(path string) []string {
+	val, ok := m.Get(path)
+	if !ok {
+		return nil
+	}
+
+	list, ok := val.([]interface{})
+	if !ok {
+		if s, ok := toString(val); ok {
+			return []string{s}
+		}
+		return nil
+	}
+
+	strs := make([]string, 0, len(list))
+	for _, item := range list {
+		if s, ok := toString(item); ok {
+			strs = append(strs, s)
+		}
+	}
+
+	return strs
+}
+
+// Int returns the value found at the specified path as an integer.
+// Numeric strings are parsed.
+func (m Matter) Int(path string) int {
+	val, _ := m.Get(path)
+	switch t := val.(type) {
+	case int:
+		return t
+	case int64:
+		return int(t)
+	case uint64:
+		return int(t)
+	case float64:
+		if t == math.Trunc(t) {
+			return int(t)
+		}
+	case string:
+		i, _ := strconv.Atoi(strings.TrimSpace(t))
+		return i
+	}
+
+	return 0
+}
+
+// Float returns the value found at the specified path as a float.
+// Numeric strings are parsed.
+func (m Matter) Float(path string) float64 {
+	val, _ := m.Get(path)
+	switch t := val.(type) {
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	case float64:
+		return t
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f
+	}
+
+	return 0
+}
+
+// Bool returns the value found at the specified path as a boolean.
+// Boolean strings are parsed.
+func (m Matter) Bool(path string) bool {
+	val, _ := m.Get(path)
+	switch t := val.(type) {
+	case bool:
+		return t
+	case string:
+		b, _ := strconv.ParseBool(strings.TrimSpace(t))
+		return b
+	}
+
+	return false
+}
+
+// Time returns the value found at the specified path as a time.
+// Strings are parsed using the `DefaultDateLayouts`, and dates which do not
+// specify a time zone use the UTC time zone. Other configurations can be
+// used by decoding the value with a `DateHook`.
+func (m Matter) Time(path string) time.Time {
+	val, _ := m.Get(path)
+	t, _ := defaultDates.toTime(val)
+	return t
+}
+
+func toString(val interface{}) (string, bool) {
+	switch t := val.(type) {
+	case string:
+		return t, true
+	case time.Time:
+		return t.Format(time.RFC3339), true
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(t), true
+	}
+
+	return "", false
+}
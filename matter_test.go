@@ -0,0 +1,104 @@
+package frontmatter_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestMatter(t *testing.T) {
+	inputs := []string{
+		`---
+title: frontmatter
+author:
+  name: John Doe
+  age: 42
+tags: ["go", "yaml"]
+draft: true
+weight: 1.5
+date: 2024-01-02
+og.title: dotted key
+---
+rest of the file`,
+		`+++
+title = "frontmatter"
+tags = ["go", "yaml"]
+draft = true
+weight = 1.5
+date = 2024-01-02
+"og.title" = "dotted key"
+
+[author]
+name = "John Doe"
+age = 42
++++
+rest of the file`,
+		`{
+  "title": "frontmatter",
+  "author": {"name": "John Doe", "age": 42},
+  "tags": ["go", "yaml"],
+  "draft": true,
+  "weight": 1.5,
+  "date": "2024-01-02",
+  "og.title": "dotted key"
+}
+
+rest of the file`,
+	}
+
+	for _, input := range inputs {
+		m, rest, err := frontmatter.ParseMatter(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("Input: `%s`\n\nunexpected error: %v", input, err)
+		}
+		if string(rest) != "rest of the file" {
+			t.Fatalf("Input: `%s`\n\nunexpected rest: %q", input, rest)
+		}
+
+		checks := []struct {
+			exp, act interface{}
+		}{
+			{"frontmatter", m.String("title")},
+			{"John Doe", m.String("author.name")},
+			{42, m.Int("author.age")},
+			{"42", m.String("author.age")},
+			{[]string{"go", "yaml"}, m.Strings("tags")},
+			{"yaml", m.String("tags.1")},
+			{[]string{"frontmatter"}, m.Strings("title")},
+			{true, m.Bool("draft")},
+			{1.5, m.Float("weight")},
+			{"dotted key", m.String("og.title")},
+			{true, m.Has("author.name")},
+			{false, m.Has("author.email")},
+			{false, m.Has("tags.2")},
+			{"", m.String("missing.key")},
+			{0, m.Int("title")},
+			{[]string{"age", "name"}, m.Map("author").Keys()},
+			{[]string{"author", "date", "draft", "og.title", "tags", "title", "weight"}, m.Keys()},
+		}
+		for i, check := range checks {
+			if !reflect.DeepEqual(check.exp, check.act) {
+				t.Fatalf("Input: `%s`\n\ncheck %d: expected %v, got %v", input, i, check.exp, check.act)
+			}
+		}
+		if date := m.Time("date").Format("2006-01-02"); date != "2024-01-02" {
+			t.Fatalf("Input: `%s`\n\nexpected date %q, got %q", input, "2024-01-02", date)
+		}
+	}
+}
+
+func TestMatterNotFound(t *testing.T) {
+	m, rest, err := frontmatter.ParseMatter(strings.NewReader("rest of the file"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m == nil || len(m) != 0 || string(rest) != "rest of the file" {
+		t.Fatalf("unexpected result: %v %q", m, rest)
+	}
+
+	if _, _, err := frontmatter.ParseMatter(strings.NewReader("---\n- go\n---\n")); err == nil {
+		t.Fatal("expected error for non-map front matter")
+	}
+}
@@ -0,0 +1,141 @@
+package frontmatter
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// ListMerge defines how lists are merged.
+type ListMerge int
+
+// List merge strategies.
+const (
+	// ListReplace replaces the default list with the new list.
+	ListReplace ListMerge = iota
+
+	// ListAppend appends the items of the new list to the default list.
+	ListAppend
+
+	// ListUnion appends the items of the new list which are not already
+	// contained by the default list.
+	ListUnion
+)
+
+// MapMerge defines how maps are merged.
+type MapMerge int
+
+// Map merge strategies.
+const (
+	// MapDeep merges the entries of the new map into the default map,
+	// recursively.
+	MapDeep MapMerge = iota
+
+	// MapReplace replaces the default map with the new map.
+	MapReplace
+)
+
+// MergeOptions holds the configuration used to merge front matters.
+type MergeOptions struct {
+	// Lists defines how lists are merged.
+	Lists ListMerge
+
+	// Maps defines how maps are merged.
+	Maps MapMerge
+}
+
+// Merge returns the result of merging the specified `values` over the
+// specified `defaults`. Values which are not maps or lists always replace
+// the defaults, while maps and lists are merged based on the provided
+// options. If no options are provided, maps are merged recursively and
+// lists are replaced. The passed in front matters are not modified.
+func Merge(defaults, values Matter, opts *MergeOptions) Matter {
+	if opts == nil {
+		opts = &MergeOptions{}
+	}
+
+	m := clone(map[string]interface{}(defaults)).(map[string]interface{})
+	return Matter(mergeMaps(m, values, opts))
+}
+
+func mergeMaps(dst, src map[string]interface{}, opts *MergeOptions) map[string]interface{} {
+	for key, val := range src {
+		dst[key] = mergeValues(dst[key], val, opts)
+	}
+
+	return dst
+}
+
+func mergeValues(dst, src interface{}, opts *MergeOptions) interface{} {
+	switch s := clone(src).(type) {
+	case map[string]interface{}:
+		if d, ok := dst.(map[string]interface{}); ok && opts.Maps == MapDeep {
+			return mergeMaps(d, s, opts)
+		}
+		return s
+	case []interface{}:
+		d, ok := dst.([]interface{})
+		if !ok {
+			return s
+		}
+
+		switch opts.Lists {
+		case ListAppend:
+			return append(d, s...)
+		case ListUnion:
+			for _, item := range s {
+				if !containsValue(d, item) {
+					d = append(d, item)
+				}
+			}
+			return d
+		}
+		return s
+	default:
+		return s
+	}
+}
+
+func containsValue(list []interface{}, val interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, val) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clone returns a deep copy of the specified value. Maps are converted
+// to maps with string keys.
+func clone(v interface{}) interface{} {
+	switch t := v.(type) {
+	case Matter:
+		return clone(map[string]interface{}(t))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[key] = clone(val)
+		}
+		return m
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for key, val := range t {
+			m[fmt.Sprint(key)] = clone(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = clone(val)
+		}
+		return s
+	case []map[string]interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = clone(val)
+		}
+		return s
+	}
+
+	return v
+}
@@ -1,32 +1,114 @@
 package frontmatter
 
 import (
-	"bufio"
 	"bytes"
 	"io"
 )
 
+// minRead is the minimum number of bytes read from the underlying reader
+// when the buffer is filled.
+const minRead = 4096
+
 type parser struct {
-	reader *bufio.Reader
-	output *bytes.Buffer
+	reader   io.Reader
+	buf      []byte
+	preamble preambleScanner
+
+	inline []byte
 
+	eof   bool
 	read  int
+	begin int
 	start int
+	stop  int
 	end   int
 }
 
 func newParser(r io.Reader) *parser {
+	// Size the buffer based on the length of the data, if available.
+	size := minRead
+	if l, ok := r.(interface{ Len() int }); ok {
+		size = l.Len() + 1
+	}
+
 	return &parser{
-		reader: bufio.NewReader(r),
-		output: bytes.NewBuffer(nil),
+		reader: r,
+		buf:    make([]byte, 0, size),
 	}
 }
 
-func (p *parser) parse(v interface{}, formats []*Format,
-	mustParse bool) ([]byte, error) {
-	// If no formats are provided, use the default ones.
+// newBytesParser returns a parser which scans the specified data in place.
+func newBytesParser(data []byte) *parser {
+	return &parser{
+		buf: data,
+		eof: true,
+	}
+}
+
+func (p *parser) parse(v interface{}, opts *Options,
+	mustParse bool) (*Document, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	// Decode the front matter into generic data first, if hooks, includes
+	// or interpolation are used.
+	hooks, includes, interp := opts.DecodeHooks, opts.Includes, opts.Interpolation
+	if len(hooks) > 0 || includes != nil || interp != nil {
+		o := *opts
+		o.DecodeHooks, o.Includes, o.Interpolation = nil, nil, nil
+
+		var data interface{}
+		doc, err := p.parse(&data, &o, mustParse)
+		if err != nil {
+			return nil, err
+		}
+		if doc.Format == nil {
+			return doc, nil
+		}
+
+		if includes != nil {
+			if data, err = includes.resolve(data); err != nil {
+				return nil, err
+			}
+		}
+		if interp != nil {
+			before := doc.Preamble
+			if opts.Tail {
+				before = doc.Body
+			}
+
+			line := bytes.Count(before, []byte{'\n'}) + 1
+			if data, err = interp.interpolate(data, doc.Matter, line); err != nil {
+				return nil, err
+			}
+		}
+		if err := DecodeWithHooks(data, v, hooks...); err != nil {
+			return nil, err
+		}
+
+		return doc, nil
+	}
+	p.preamble.preamble = opts.Preamble
+
+	// If no formats are provided, use the default ones. The default formats
+	// are shared, so documents are given copies of them.
+	formats, shared := opts.Formats, false
 	if len(formats) == 0 {
-		formats = defaultFormats()
+		formats, shared = defaults, true
+		if opts.HTMLComments {
+			formats = htmlDefaults
+		}
+	}
+
+	// Search for the front matter at the end of the data, if required.
+	if opts.Tail {
+		doc, err := p.parseTail(v, formats, mustParse)
+		if err == nil && shared && doc.Format != nil {
+			f := *doc.Format
+			doc.Format = &f
+		}
+		return doc, err
 	}
 
 	// Detect format.
@@ -47,11 +129,26 @@ func (p *parser) parse(v interface{}, formats []*Format,
 	}
 
 	// Read remaining data.
-	if _, err := p.output.ReadFrom(p.reader); err != nil {
+	if err := p.readAll(); err != nil {
 		return nil, err
 	}
 
-	return p.output.Bytes()[p.end:], nil
+	data := p.buf
+	if !found {
+		return &Document{Body: data}, nil
+	}
+
+	if shared {
+		c := *f
+		f = &c
+	}
+
+	doc := &Document{Format: f, Matter: data[p.begin:p.stop], Body: data[p.end:]}
+	if p.begin > 0 {
+		doc.Preamble = data[:p.begin]
+	}
+
+	return doc, nil
 }
 
 func (p *parser) detect(formats []*Format) (*Format, error) {
@@ -59,15 +156,36 @@ func (p *parser) detect(formats []*Format) (*Format, error) {
 		read := p.read
 
 		line, atEOF, err := p.readLine()
-		if err != nil || atEOF {
+		if err != nil {
 			return nil, err
 		}
-		if line == "" {
+		if p.preamble.pending(line) || len(line) == 0 {
+			if atEOF {
+				return nil, nil
+			}
 			continue
 		}
 
 		for _, f := range formats {
-			if f.Start == line {
+			if f.isHeader() {
+				if f.header(line, true) {
+					p.begin, p.start = read, read
+					return f, nil
+				}
+				continue
+			}
+
+			delim, ok := f.trim(line)
+			if !ok {
+				continue
+			}
+			if data, ok := f.inline(delim); ok {
+				p.begin, p.start = read, read
+				p.inline = data
+				return f, nil
+			}
+			if string(delim) == f.Start || (f.Scan != nil && hasPrefix(delim, f.Start)) {
+				p.begin = read
 				if !f.UnmarshalDelims {
 					read = p.read
 				}
@@ -76,12 +194,35 @@ func (p *parser) detect(formats []*Format) (*Format, error) {
 				return f, nil
 			}
 		}
+		if !atEOF && p.preamble.skip(line) {
+			continue
+		}
 
 		return nil, nil
 	}
 }
 
 func (p *parser) extract(f *Format, v interface{}) (bool, error) {
+	if p.inline != nil {
+		if err := f.Unmarshal(p.inline, v); err != nil {
+			return false, err
+		}
+
+		p.stop, p.end = p.read, p.read
+		return true, nil
+	}
+
+	if f.isHeader() {
+		return p.extractHeader(f, v)
+	}
+	if f.Scan != nil {
+		return p.extractScan(f, v)
+	}
+
+	return p.extractDelims(f, v)
+}
+
+func (p *parser) extractDelims(f *Format, v interface{}) (bool, error) {
 	for {
 		read := p.read
 
@@ -90,18 +231,24 @@ func (p *parser) extract(f *Format, v interface{}) (bool, error) {
 			return false, err
 		}
 
+		delim, ok := f.trim(line)
+		if !ok {
+			return false, nil
+		}
+
 	CheckLine:
-		if line != f.End {
+		if string(delim) != f.End {
 			if atEOF {
 				return false, err
 			}
 			continue
 		}
+		p.stop = p.read
 		if f.RequiresNewLine {
 			if line, atEOF, err = p.readLine(); err != nil {
 				return false, err
 			}
-			if line != "" {
+			if delim, ok = f.trim(line); ok && len(delim) != 0 {
 				goto CheckLine
 			}
 		}
@@ -109,7 +256,8 @@ func (p *parser) extract(f *Format, v interface{}) (bool, error) {
 			read = p.read
 		}
 
-		if err := f.Unmarshal(p.output.Bytes()[p.start:read], v); err != nil {
+		data := f.strip(p.buf[p.start:read])
+		if err := f.Unmarshal(data, v); err != nil {
 			return false, err
 		}
 
@@ -118,15 +266,217 @@ func (p *parser) extract(f *Format, v interface{}) (bool, error) {
 	}
 }
 
-func (p *parser) readLine() (string, bool, error) {
-	line, err := p.reader.ReadBytes('\n')
+func (p *parser) extractHeader(f *Format, v interface{}) (bool, error) {
+	// Up to two unprefixed lines (e.g. the AsciiDoc author and revision
+	// lines) can follow the title line. They are part of the front matter,
+	// but they are not unmarshaled.
+	title, extra := -1, 0
+	if f.Title != "" && !f.header(bytes.TrimSpace(p.buf[p.start:p.read]), false) {
+		title = p.read
+	}
 
-	atEOF := err == io.EOF
-	if err != nil && !atEOF {
-		return "", false, err
+	read, end, skip := p.read, p.read, p.read
+	for {
+		line, atEOF, err := p.readLine()
+		if err != nil {
+			return false, err
+		}
+
+		header := len(line) != 0 && f.header(line, false)
+		if !header && len(line) != 0 && title >= 0 && read == skip && extra < 2 {
+			header, skip = true, p.read
+			extra++
+		}
+		if header {
+			read, end = p.read, p.read
+			if atEOF {
+				break
+			}
+			continue
+		}
+		if len(line) == 0 {
+			// The empty line ending the front matter is not part of the body.
+			end = p.read
+		}
+		break
+	}
+
+	data := p.buf[p.start:read]
+	if extra > 0 {
+		data = append(append([]byte{}, p.buf[p.start:title]...), p.buf[skip:read]...)
+	}
+	if err := f.Unmarshal(f.strip(data), v); err != nil {
+		return false, err
+	}
+
+	p.stop, p.end = read, end
+	return true, nil
+}
+
+func (p *parser) extractScan(f *Format, v interface{}) (bool, error) {
+	if err := p.readAll(); err != nil {
+		return false, err
+	}
+	read := p.read
+
+	// Scan the front matter, starting at the line containing the start
+	// delimiter. For prefixed formats, only the comment block is scanned.
+	block := p.buf[p.begin:]
+	if f.Prefix != "" {
+		block = block[:prefixedLen(block, f.Prefix)]
+	}
+	data := f.strip(block)
+
+	n, err := f.Scan(data)
+	if err == nil {
+		// The rest of the line must be empty.
+		rest := data[n:]
+		if i := bytes.IndexByte(rest, '\n'); i >= 0 {
+			rest = rest[:i+1]
+		}
+
+		if len(bytes.TrimSpace(rest)) == 0 {
+			// Skip the lines containing the front matter.
+			p.read = p.begin + len(block)
+			if bytes.HasSuffix(rest, []byte{'\n'}) {
+				lines := bytes.Count(data[:n+len(rest)], []byte{'\n'})
+				p.read = p.begin + skipLines(block, lines)
+			}
+
+			p.stop = p.read
+			found := true
+			if f.RequiresNewLine {
+				line, _, err := p.readLine()
+				if err != nil {
+					return false, err
+				}
+				if delim, ok := f.trim(line); ok && len(delim) != 0 {
+					found = false
+				}
+			}
+			if found {
+				if err := f.Unmarshal(data[:n], v); err != nil {
+					return false, err
+				}
+
+				p.end = p.read
+				return true, nil
+			}
+		}
+	}
+
+	// Fall back to searching for the end delimiter, in order to report
+	// decoding errors for invalid front matters, but only if the first
+	// line consists of the start delimiter. Otherwise, the data is not
+	// considered a front matter (e.g. `{{< shortcode >}}` lines).
+	first := block
+	if i := bytes.IndexByte(first, '\n'); i >= 0 {
+		first = first[:i]
+	}
+	if delim, ok := f.trim(bytes.TrimSpace(first)); !ok || string(delim) != f.Start {
+		return false, nil
+	}
+
+	p.read = read
+	return p.extractDelims(f, v)
+}
+
+// readLine returns the next line of the input data, with the leading and
+// trailing whitespace removed, and reports whether the line is the last one.
+// The returned line references the internal buffer and it is only valid
+// until the next read.
+func (p *parser) readLine() ([]byte, bool, error) {
+	i := bytes.IndexByte(p.buf[p.read:], '\n')
+	for i < 0 && !p.eof {
+		n := len(p.buf)
+		if err := p.fill(); err != nil {
+			return nil, false, err
+		}
+		if j := bytes.IndexByte(p.buf[n:], '\n'); j >= 0 {
+			i = n - p.read + j
+		}
+	}
+
+	line, atEOF := p.buf[p.read:], true
+	if i >= 0 {
+		line, atEOF = line[:i+1], false
 	}
 
 	p.read += len(line)
-	_, err = p.output.Write(line)
-	return string(bytes.TrimSpace(line)), atEOF, err
+	return bytes.TrimSpace(line), atEOF, nil
+}
+
+// readAll reads the remaining data from the underlying reader.
+func (p *parser) readAll() error {
+	for !p.eof {
+		if err := p.fill(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fill reads data from the underlying reader into the buffer, growing it
+// if necessary.
+func (p *parser) fill() error {
+	if len(p.buf) == cap(p.buf) {
+		buf := make([]byte, len(p.buf), 2*cap(p.buf)+minRead)
+		copy(buf, p.buf)
+		p.buf = buf
+	}
+
+	n, err := p.reader.Read(p.buf[len(p.buf):cap(p.buf)])
+	p.buf = p.buf[:len(p.buf)+n]
+	if err == io.EOF {
+		p.eof = true
+		return nil
+	}
+
+	return err
+}
+
+// prefixedLen returns the length of the block of contiguous lines starting
+// with the specified prefix, at the beginning of the data.
+func prefixedLen(data []byte, prefix string) int {
+	n := 0
+	for n < len(data) {
+		line := data[n:]
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+		if !hasPrefix(bytes.TrimLeft(line, " \t"), prefix) {
+			break
+		}
+		n += len(line)
+	}
+
+	return n
+}
+
+// skipLines returns the offset of the data following the specified
+// number of lines.
+func skipLines(data []byte, lines int) int {
+	n := 0
+	for ; lines > 0; lines-- {
+		i := bytes.IndexByte(data[n:], '\n')
+		if i < 0 {
+			return len(data)
+		}
+		n += i + 1
+	}
+
+	return n
+}
+
+// hasPrefix reports whether the specified data begins with prefix,
+// without converting the prefix to a byte slice.
+func hasPrefix(data []byte, prefix string) bool {
+	return len(data) >= len(prefix) && string(data[:len(prefix)]) == prefix
+}
+
+// hasSuffix reports whether the specified data ends with suffix,
+// without converting the suffix to a byte slice.
+func hasSuffix(data []byte, suffix string) bool {
+	return len(data) >= len(suffix) && string(data[len(data)-len(suffix):]) == suffix
 }
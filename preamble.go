@@ -0,0 +1,87 @@
+package frontmatter
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// Comment describes the syntax of a comment.
+type Comment struct {
+	// Start defines the starting delimiter of the comment.
+	// E.g.: `#`, `//` or `<!--`.
+	Start string
+
+	// End defines the ending delimiter of block comments.
+	// Should be empty for line comments.
+	// E.g.: `-->`.
+	End string
+}
+
+// Preamble describes the content which is allowed to precede a front
+// matter, such as shebang lines, license headers or HTML comments.
+// Empty lines are always skipped, regardless of the preamble configuration.
+type Preamble struct {
+	// Lines specifies the number of leading lines which are skipped,
+	// regardless of their content.
+	Lines int
+
+	// Pattern matches the lines which are skipped.
+	// E.g.: regexp.MustCompile(`^#!`).
+	Pattern *regexp.Regexp
+
+	// Comments defines the line and block comments which are skipped.
+	// E.g.: Comment{Start: "#"} or Comment{Start: "<!--", End: "-->"}.
+	Comments []Comment
+}
+
+type preambleScanner struct {
+	preamble *Preamble
+	comment  *Comment
+	lines    int
+}
+
+// pending reports whether the specified line must be skipped before any
+// front matter detection is attempted (leading lines or the rest of an open
+// block comment).
+func (s *preambleScanner) pending(line []byte) bool {
+	if s.preamble == nil {
+		return false
+	}
+
+	s.lines++
+	if s.lines <= s.preamble.Lines {
+		return true
+	}
+	if s.comment != nil {
+		if bytes.Contains(line, []byte(s.comment.End)) {
+			s.comment = nil
+		}
+		return true
+	}
+
+	return false
+}
+
+// skip reports whether the specified line is part of the preamble.
+func (s *preambleScanner) skip(line []byte) bool {
+	if s.preamble == nil {
+		return false
+	}
+	if s.preamble.Pattern != nil && s.preamble.Pattern.Match(line) {
+		return true
+	}
+
+	for i := range s.preamble.Comments {
+		c := &s.preamble.Comments[i]
+		if c.Start == "" || !hasPrefix(line, c.Start) {
+			continue
+		}
+		if c.End != "" && !bytes.Contains(line[len(c.Start):], []byte(c.End)) {
+			s.comment = c
+		}
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package query
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Options holds the configuration used to find documents.
+type Options struct {
+	// Pattern defines the pattern matched against the names of the
+	// documents, using the syntax of `path.Match`. If empty, `*.md` is used.
+	Pattern string
+
+	// Parse holds the configuration used to parse the documents.
+	Parse *frontmatter.Options
+}
+
+// Result describes a document matching a query.
+type Result struct {
+	// Name is the slash-separated path of the document.
+	Name string
+
+	// Matter is the front matter of the document.
+	Matter frontmatter.Matter
+}
+
+// Find returns the documents found in the specified directory and its
+// subdirectories whose front matter matches the query, sorted by name.
+// Documents without front matter are matched against an empty front matter.
+// The search stops at the first document which cannot be read, parsed or
+// matched, in which case no results are returned, and the error is prefixed
+// by the name of the document.
+func Find(fsys fs.FS, root string, q *Query, opts *Options) ([]Result, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	var results []Result
+	err := frontmatter.Walk(fsys, root, opts.Pattern, func(name string, d fs.DirEntry) error {
+		m, _, err := frontmatter.ParseFile(fsys, name, opts.Parse)
+		if err != nil {
+			return err
+		}
+
+		ok, err := q.Match(m)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if ok {
+			results = append(results, Result{Name: name, Matter: m})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
@@ -0,0 +1,824 @@
+// Package query implements a small expression language used to filter
+// documents based on their front matter.
+//
+// Expressions consist of comparisons combined using the `&&`, `||` and `!`
+// operators, and grouped using parentheses. E.g.:
+//
+//	draft == true && !has(author)
+//	"go" in tags || lower(title) matches "^intro"
+//	date >= 2024-01-01 && len(tags) > 2
+//
+// Operands can be front matter paths (e.g. `author.name` or `tags.0`),
+// strings (double or single quoted), numbers, booleans, `null`, dates
+// (e.g. `2024-01-02` or `2024-01-02T15:04:05Z`) and lists (e.g.
+// `["go", "yaml"]`). Paths which are not found evaluate to `null`.
+//
+// The supported operators are:
+//   - `==`, `!=`: equality. Numbers are compared regardless of their type,
+//     and dates are compared with strings containing dates.
+//   - `<`, `<=`, `>`, `>=`: ordering of numbers, strings and dates.
+//     Comparisons involving `null` or values of different types are false.
+//   - `in`: membership of a value in a list, of a substring in a string,
+//     or of a key in a map.
+//   - `matches`: regular expression matching.
+//
+// Comparisons bind tighter than `!`, which binds tighter than `&&`, which
+// binds tighter than `||`. E.g.: `!"go" in tags` is evaluated as
+// `!("go" in tags)`. Comparisons cannot be chained.
+//
+// The supported functions are `has(path)`, which reports whether a path
+// exists, `len(value)`, which returns the length of a list, map or string,
+// and `lower(value)`, `upper(value)`, which change the case of strings.
+//
+// Values used as conditions are true unless they are `null`, `false`, `0`,
+// or empty strings, lists or maps.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Query is a parsed query expression.
+type Query struct {
+	src  string
+	root node
+}
+
+// Parse parses the specified query expression.
+func Parse(expr string) (*Query, error) {
+	p := &parser{lexer: lexer{src: expr}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+
+	return &Query{src: expr, root: root}, nil
+}
+
+// MustParse is similar to `Parse`, but it panics if the expression cannot
+// be parsed.
+func MustParse(expr string) *Query {
+	q, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return q
+}
+
+// String returns the source of the query expression.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Match reports whether the specified front matter matches the query.
+func (q *Query) Match(m frontmatter.Matter) (bool, error) {
+	v, err := q.Eval(m)
+	if err != nil {
+		return false, err
+	}
+
+	return truthy(v), nil
+}
+
+// Eval evaluates the query expression against the specified front matter,
+// and returns the resulting value.
+func (q *Query) Eval(m frontmatter.Matter) (interface{}, error) {
+	return q.root.eval(m)
+}
+
+// Lexer.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokDate
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	val  interface{}
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokEOF {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+var (
+	dateRegexp     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}(:\d{2}(\.\d+)?)?(Z|[+-]\d{2}:\d{2})?)?`)
+	numberRegexp   = regexp.MustCompile(`^-?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?`)
+	operatorTokens = []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")", "[", "]", ","}
+)
+
+func (l *lexer) token() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(rune(l.src[l.pos])) {
+		l.pos++
+	}
+
+	pos := l.pos
+	if pos >= len(l.src) {
+		return token{kind: tokEOF, pos: pos}, nil
+	}
+
+	rest := l.src[pos:]
+	switch c := rest[0]; {
+	case c == '"' || c == '\'':
+		return l.lexString(c)
+	case c >= '0' && c <= '9' || c == '-' || c == '.':
+		if m := dateRegexp.FindString(rest); m != "" {
+			t, err := parseDate(m)
+			if err != nil {
+				return token{}, fmt.Errorf("query: position %d: invalid date %q", pos+1, m)
+			}
+			l.pos += len(m)
+			return token{kind: tokDate, text: m, val: t, pos: pos}, nil
+		}
+		if m := numberRegexp.FindString(rest); m != "" {
+			f, err := strconv.ParseFloat(m, 64)
+			if err != nil {
+				return token{}, fmt.Errorf("query: position %d: invalid number %q", pos+1, m)
+			}
+			l.pos += len(m)
+			return token{kind: tokNumber, text: m, val: f, pos: pos}, nil
+		}
+	case isIdentStart(rune(c)) || c >= utf8.RuneSelf:
+		end := len(rest)
+		for i, r := range rest {
+			if !isIdentStart(r) && !unicode.IsDigit(r) && r != '-' && r != '.' && r != '$' {
+				end = i
+				break
+			}
+		}
+		l.pos += end
+		return token{kind: tokIdent, text: rest[:end], pos: pos}, nil
+	}
+
+	for _, op := range operatorTokens {
+		if strings.HasPrefix(rest, op) {
+			l.pos += len(op)
+			return token{kind: tokOp, text: op, pos: pos}, nil
+		}
+	}
+
+	r, _ := utf8.DecodeRuneInString(rest)
+	return token{}, fmt.Errorf("query: position %d: unexpected character %q", pos+1, r)
+}
+
+func (l *lexer) lexString(quote byte) (token, error) {
+	pos := l.pos
+	var sb strings.Builder
+	for i := pos + 1; i < len(l.src); i++ {
+		switch c := l.src[i]; c {
+		case quote:
+			l.pos = i + 1
+			return token{kind: tokString, text: l.src[pos:l.pos], val: sb.String(), pos: pos}, nil
+		case '\\':
+			if i+1 >= len(l.src) {
+				break
+			}
+			i++
+			switch c = l.src[i]; c {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(c)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return token{}, fmt.Errorf("query: position %d: unterminated string", pos+1)
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+// Parser.
+
+type parser struct {
+	lexer
+	tok token
+}
+
+func (p *parser) next() error {
+	tok, err := p.token()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) is(op string) bool {
+	return p.tok.kind == tokOp && p.tok.text == op
+}
+
+func (p *parser) isKeyword(kw string) bool {
+	return p.tok.kind == tokIdent && p.tok.text == kw
+}
+
+func (p *parser) expect(op string) error {
+	if !p.is(op) {
+		return p.errorf("expected %q, found %s", op, p.tok)
+	}
+
+	return p.next()
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("query: position %d: %s", p.tok.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is("||") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{or: true, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.is("&&") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalNode{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.is("!") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{operand: operand}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	var op string
+	switch {
+	case p.is("=="), p.is("!="), p.is("<"), p.is("<="), p.is(">"), p.is(">="),
+		p.isKeyword("in"), p.isKeyword("matches"):
+		op = p.tok.text
+	default:
+		return left, nil
+	}
+
+	pos := p.tok.pos
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if op == "matches" {
+		lit, ok := right.(*literalNode)
+		s, isString := lit.value().(string)
+		if !ok || !isString {
+			return nil, fmt.Errorf("query: position %d: matches requires a string pattern", pos+1)
+		}
+
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return nil, fmt.Errorf("query: position %d: invalid pattern: %v", pos+1, err)
+		}
+		return &matchNode{operand: left, re: re}, nil
+	}
+
+	return &compareNode{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokString, tokNumber, tokDate:
+		return &literalNode{val: tok.val}, p.next()
+	case tokIdent:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		switch tok.text {
+		case "true":
+			return &literalNode{val: true}, nil
+		case "false":
+			return &literalNode{val: false}, nil
+		case "null":
+			return &literalNode{}, nil
+		}
+		if p.is("(") {
+			return p.parseCall(tok)
+		}
+		return &pathNode{path: tok.text}, nil
+	case tokOp:
+		switch tok.text {
+		case "(":
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+
+			expr, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		case "[":
+			return p.parseList()
+		}
+	}
+
+	return nil, p.errorf("unexpected %s", tok)
+}
+
+func (p *parser) parseList() (node, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	list := &listNode{}
+	for !p.is("]") {
+		item, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		list.items = append(list.items, item)
+
+		if !p.is(",") {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return list, p.expect("]")
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var args []node
+	for !p.is(")") {
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if !p.is(",") {
+			break
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("query: position %d: %s", name.pos+1, fmt.Sprintf(format, args...))
+	}
+	if len(args) != 1 {
+		return nil, errorf("%s expects 1 argument, got %d", name.text, len(args))
+	}
+
+	switch name.text {
+	case "has":
+		path, ok := args[0].(*pathNode)
+		if !ok {
+			return nil, errorf("has expects a path argument")
+		}
+		return &hasNode{path: path.path}, nil
+	case "len", "lower", "upper":
+		return &callNode{name: name.text, arg: args[0]}, nil
+	}
+
+	return nil, errorf("unknown function %q", name.text)
+}
+
+// Nodes.
+
+type node interface {
+	eval(m frontmatter.Matter) (interface{}, error)
+}
+
+type literalNode struct {
+	val interface{}
+}
+
+func (n *literalNode) value() interface{} {
+	if n == nil {
+		return nil
+	}
+
+	return n.val
+}
+
+func (n *literalNode) eval(frontmatter.Matter) (interface{}, error) {
+	return n.val, nil
+}
+
+type listNode struct {
+	items []node
+}
+
+func (n *listNode) eval(m frontmatter.Matter) (interface{}, error) {
+	list := make([]interface{}, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(m)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+
+	return list, nil
+}
+
+type pathNode struct {
+	path string
+}
+
+func (n *pathNode) eval(m frontmatter.Matter) (interface{}, error) {
+	v, _ := m.Get(n.path)
+	return v, nil
+}
+
+type hasNode struct {
+	path string
+}
+
+func (n *hasNode) eval(m frontmatter.Matter) (interface{}, error) {
+	return m.Has(n.path), nil
+}
+
+type callNode struct {
+	name string
+	arg  node
+}
+
+func (n *callNode) eval(m frontmatter.Matter) (interface{}, error) {
+	v, err := n.arg.eval(m)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.name {
+	case "len":
+		switch t := v.(type) {
+		case nil:
+			return float64(0), nil
+		case string:
+			return float64(utf8.RuneCountInString(t)), nil
+		}
+
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return float64(rv.Len()), nil
+		}
+		return nil, fmt.Errorf("query: cannot compute the length of %T", v)
+	case "lower", "upper":
+		s, ok := v.(string)
+		if !ok {
+			return v, nil
+		}
+		if n.name == "lower" {
+			return strings.ToLower(s), nil
+		}
+		return strings.ToUpper(s), nil
+	}
+
+	return nil, fmt.Errorf("query: unknown function %q", n.name)
+}
+
+type notNode struct {
+	operand node
+}
+
+func (n *notNode) eval(m frontmatter.Matter) (interface{}, error) {
+	v, err := n.operand.eval(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return !truthy(v), nil
+}
+
+type logicalNode struct {
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) eval(m frontmatter.Matter) (interface{}, error) {
+	v, err := n.left.eval(m)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(v) == n.or {
+		return n.or, nil
+	}
+
+	if v, err = n.right.eval(m); err != nil {
+		return nil, err
+	}
+	return truthy(v), nil
+}
+
+type matchNode struct {
+	operand node
+	re      *regexp.Regexp
+}
+
+func (n *matchNode) eval(m frontmatter.Matter) (interface{}, error) {
+	v, err := n.operand.eval(m)
+	if err != nil {
+		return nil, err
+	}
+
+	s, ok := v.(string)
+	return ok && n.re.MatchString(s), nil
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n *compareNode) eval(m frontmatter.Matter) (interface{}, error) {
+	a, err := n.left.eval(m)
+	if err != nil {
+		return nil, err
+	}
+	b, err := n.right.eval(m)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(a, b), nil
+	case "!=":
+		return !equal(a, b), nil
+	case "in":
+		return contains(b, a), nil
+	}
+
+	c, ok := compare(a, b)
+	if !ok {
+		return false, nil
+	}
+
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case ">=":
+		return c >= 0, nil
+	}
+
+	return nil, fmt.Errorf("query: unknown operator %q", n.op)
+}
+
+// Values.
+
+func truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	}
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map:
+		return rv.Len() > 0
+	}
+
+	return true
+}
+
+func equal(a, b interface{}) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	la, ok1 := a.([]interface{})
+	lb, ok2 := b.([]interface{})
+	if ok1 && ok2 {
+		if len(la) != len(lb) {
+			return false
+		}
+		for i := range la {
+			if !equal(la[i], lb[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// compare compares the specified values, and reports whether they can be
+// compared. Numbers, strings, booleans and dates can be compared.
+func compare(a, b interface{}) (int, bool) {
+	if fa, ok := toFloat(a); ok {
+		if fb, ok := toFloat(b); ok {
+			switch {
+			case fa < fb:
+				return -1, true
+			case fa > fb:
+				return 1, true
+			}
+			return 0, true
+		}
+		return 0, false
+	}
+
+	_, ta := a.(time.Time)
+	_, tb := b.(time.Time)
+	if ta || tb {
+		da, ok1 := toTime(a)
+		db, ok2 := toTime(b)
+		if !ok1 || !ok2 {
+			return 0, false
+		}
+		switch {
+		case da.Before(db):
+			return -1, true
+		case da.After(db):
+			return 1, true
+		}
+		return 0, true
+	}
+
+	switch ta := a.(type) {
+	case string:
+		if tb, ok := b.(string); ok {
+			return strings.Compare(ta, tb), true
+		}
+	case bool:
+		if tb, ok := b.(bool); ok && ta == tb {
+			return 0, true
+		}
+	}
+
+	return 0, false
+}
+
+func contains(container, v interface{}) bool {
+	switch t := container.(type) {
+	case []interface{}:
+		for _, item := range t {
+			if equal(item, v) {
+				return true
+			}
+		}
+	case string:
+		s, ok := v.(string)
+		return ok && strings.Contains(t, s)
+	case map[string]interface{}:
+		s, ok := v.(string)
+		if ok {
+			_, ok = t[s]
+		}
+		return ok
+	case map[interface{}]interface{}:
+		// Compare the keys, as the value may not be hashable.
+		for key := range t {
+			if equal(key, v) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case uint64:
+		return float64(t), true
+	case float64:
+		return t, true
+	}
+
+	return 0, false
+}
+
+func toTime(v interface{}) (time.Time, bool) {
+	var d frontmatter.Date
+	if err := frontmatter.Decode(v, &d); err != nil || v == nil {
+		return time.Time{}, false
+	}
+
+	return d.Time, true
+}
+
+func parseDate(s string) (time.Time, error) {
+	t, ok := toTime(s)
+	if !ok {
+		return time.Time{}, errors.New("invalid date")
+	}
+
+	return t, nil
+}
@@ -0,0 +1,158 @@
+package query_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/query"
+)
+
+func TestMatch(t *testing.T) {
+	m := frontmatter.Matter{
+		"title":   "Intro to Go",
+		"draft":   true,
+		"tags":    []interface{}{"go", "yaml"},
+		"weight":  int64(10),
+		"ratio":   1.5,
+		"date":    "2024-02-03",
+		"updated": time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+		"author":  map[string]interface{}{"name": "John Doe"},
+		"empty":   "",
+		"ids":     map[interface{}]interface{}{1: "a", "b": true},
+	}
+
+	testCases := []struct {
+		expr  string
+		match bool
+	}{
+		{`draft == true && !has(author)`, false},
+		{`draft == true && !has(editor)`, true},
+		{`draft`, true},
+		{`!draft || missing`, false},
+		{`"go" in tags`, true},
+		{`"toml" in tags`, false},
+		{`!"toml" in tags`, true},
+		{`!"go" in tags || !draft && draft`, false},
+		{`"Go" in title`, true},
+		{`"name" in author`, true},
+		{`1 in ids && "b" in ids`, true},
+		{`tags in ids`, false},
+		{`date > 2024-01-01`, true},
+		{`date > 2024-02-03`, false},
+		{`date == 2024-02-03`, true},
+		{`updated >= 2024-03-04T10:00:00Z && updated < 2024-03-05`, true},
+		{`weight == 10 && weight > 9.5 && ratio < 2`, true},
+		{`weight == "10"`, false},
+		{`title < "J" && title >= "Intro"`, true},
+		{`author.name == 'John Doe'`, true},
+		{`tags.1 == "yaml"`, true},
+		{`tags == ["go", "yaml"]`, true},
+		{`weight in [1, 10]`, true},
+		{`len(tags) == 2 && len(title) > 5 && len(missing) == 0`, true},
+		{`lower(title) matches "^intro"`, true},
+		{`upper(title) == "INTRO TO GO"`, true},
+		{`title matches "^go"`, false},
+		{`missing == null && author != null`, true},
+		{`missing > 1 || missing < 1`, false},
+		{`empty || (ratio > 1 && (weight < 0 || draft))`, true},
+		{`!(tags)`, false},
+	}
+
+	for _, tc := range testCases {
+		q, err := query.Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Expr: `%s`\n\nunexpected error: %v", tc.expr, err)
+		}
+
+		match, err := q.Match(m)
+		if err != nil {
+			t.Fatalf("Expr: `%s`\n\nunexpected error: %v", tc.expr, err)
+		}
+		if match != tc.match {
+			t.Fatalf("Expr: `%s`\n\nexpected match %t, got %t", tc.expr, tc.match, match)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		expr string
+		err  string
+	}{
+		{``, "query: position 1: unexpected end of expression"},
+		{`draft ==`, "query: position 9: unexpected end of expression"},
+		{`draft == true)`, `query: position 14: unexpected ")"`},
+		{`(draft`, `query: position 7: expected ")", found end of expression`},
+		{`"go in tags`, "query: position 1: unterminated string"},
+		{`has("go")`, "query: position 1: has expects a path argument"},
+		{`size(tags) > 1`, `query: position 1: unknown function "size"`},
+		{`len(a, b)`, "query: position 1: len expects 1 argument, got 2"},
+		{`title matches tags`, "query: position 7: matches requires a string pattern"},
+		{`title matches "("`, "query: position 7: invalid pattern"},
+		{`date > 2024-13-01`, `query: position 8: invalid date "2024-13-01"`},
+		{`draft = true`, `query: position 7: unexpected character '='`},
+		{`draft == true == true`, `query: position 15: unexpected "=="`},
+	}
+
+	for _, tc := range testCases {
+		_, err := query.Parse(tc.expr)
+		if err == nil || len(err.Error()) < len(tc.err) || err.Error()[:len(tc.err)] != tc.err {
+			t.Fatalf("Expr: `%s`\n\nexpected error %q, got %v", tc.expr, tc.err, err)
+		}
+	}
+
+	q := query.MustParse(`len(draft) > 0`)
+	if _, err := q.Match(frontmatter.Matter{"draft": true}); err == nil {
+		t.Fatal("expected evaluation error")
+	}
+}
+
+func TestFind(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.md":        {Data: []byte("---\ntitle: A\ndraft: true\ntags: [go]\ndate: 2024-01-02\n---\n")},
+		"b.md":        {Data: []byte("+++\ntitle = \"B\"\ndraft = true\nauthor = \"John\"\ndate = 2023-05-06\n+++\n")},
+		"blog/c.md":   {Data: []byte("{\n  \"title\": \"C\", \"draft\": true, \"date\": \"2024-06-07\"\n}\n")},
+		"blog/d.md":   {Data: []byte("no front matter")},
+		"blog/e.html": {Data: []byte("---\ntitle: E\ndraft: true\n---\n")},
+	}
+
+	testCases := []struct {
+		expr  string
+		names []string
+	}{
+		{`draft == true && !has(author)`, []string{"a.md", "blog/c.md"}},
+		{`"go" in tags`, []string{"a.md"}},
+		{`date > 2024-01-01`, []string{"a.md", "blog/c.md"}},
+		{`!has(title)`, []string{"blog/d.md"}},
+	}
+
+	for _, tc := range testCases {
+		results, err := query.Find(fsys, ".", query.MustParse(tc.expr), nil)
+		if err != nil {
+			t.Fatalf("Expr: `%s`\n\nunexpected error: %v", tc.expr, err)
+		}
+
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		if !reflect.DeepEqual(names, tc.names) {
+			t.Fatalf("Expr: `%s`\n\nexpected %v, got %v", tc.expr, tc.names, names)
+		}
+	}
+
+	results, err := query.Find(fsys, "blog", query.MustParse(`draft`), &query.Options{Pattern: "*.html"})
+	if err != nil || len(results) != 1 || results[0].Matter.String("title") != "E" {
+		t.Fatalf("unexpected results: %v %v", results, err)
+	}
+
+	fsys["invalid.md"] = &fstest.MapFile{Data: []byte("---\ntitle: [\n---\n")}
+	results, err = query.Find(fsys, ".", query.MustParse(`draft`), nil)
+	if err == nil || results != nil || !strings.HasPrefix(err.Error(), "invalid.md: ") {
+		t.Fatalf("expected error for invalid document, got %v %v", results, err)
+	}
+}
@@ -0,0 +1,110 @@
+package frontmatter
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// Sidecar loads the metadata of files from sidecar files (e.g. the
+// `photo.jpg.yaml` file provides the metadata of `photo.jpg`), merged with
+// the front matter of the files themselves, if any.
+//
+// The sidecars are merged in the order of the patterns, each sidecar
+// overriding the values of the previous ones. By default, the front matter
+// of the file overrides the values of all sidecars. If `PreferSidecars` is
+// set, the values of the sidecars override the front matter of the file.
+type Sidecar struct {
+	// FS is the file system containing the files and their sidecars.
+	FS fs.FS
+
+	// Patterns defines the names of the sidecar files, relative to the
+	// directory of the file. The `{name}` placeholder is replaced with the
+	// name of the file, and `{base}` with the name of the file, without
+	// its extension. If no patterns are provided, the `{name}.yaml`,
+	// `{name}.yml`, `{name}.toml` and `{name}.json` patterns are used.
+	// E.g.: `{base}.meta.yaml` or `.meta/{name}.json`.
+	Patterns []string
+
+	// Pattern defines the pattern matched against the names of the files
+	// which can contain front matter, using the syntax of `path.Match`.
+	// Other files (e.g. images) are not read. If empty, `*.md` is used.
+	Pattern string
+
+	// PreferSidecars specifies whether the values of the sidecars override
+	// the front matter of the file.
+	PreferSidecars bool
+
+	// Unmarshalers maps file extensions to the unmarshal functions used to
+	// decode the sidecars. If nil, the YAML (`.yaml` and `.yml`), TOML
+	// (`.toml`) and JSON (`.json`) unmarshalers are used.
+	Unmarshalers map[string]UnmarshalFunc
+
+	// Merge defines how the values of the sidecars and of the front
+	// matter are merged.
+	Merge MergeOptions
+
+	// Options holds the configuration used to parse the front matter of
+	// the files. The `DecodeHooks` are not used, and the `Includes` are
+	// resolved relative to each file.
+	Options *Options
+}
+
+// Load returns the metadata of the specified file, along with its body.
+// The body is nil if the file cannot contain front matter.
+func (s *Sidecar) Load(name string) (Matter, []byte, error) {
+	name = path.Clean(name)
+	if _, err := fs.Stat(s.FS, name); err != nil {
+		return nil, nil, err
+	}
+
+	m := Matter{}
+	for _, sidecar := range s.Sidecars(name) {
+		vals, err := readData(s.FS, s.Unmarshalers, sidecar)
+		if err != nil {
+			return nil, nil, err
+		}
+		if vals != nil {
+			m = Merge(m, vals, &s.Merge)
+		}
+	}
+
+	pattern := s.Pattern
+	if pattern == "" {
+		pattern = "*.md"
+	}
+	if ok, err := path.Match(pattern, path.Base(name)); err != nil || !ok {
+		return m, nil, err
+	}
+
+	vals, body, err := parseFile(s.FS, s.Options, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.PreferSidecars {
+		return Merge(vals, m, &s.Merge), body, nil
+	}
+
+	return Merge(m, vals, &s.Merge), body, nil
+}
+
+// Sidecars returns the names of the potential sidecar files of the
+// specified file, in the order of the patterns. The files are not
+// required to exist.
+func (s *Sidecar) Sidecars(name string) []string {
+	patterns := s.Patterns
+	if len(patterns) == 0 {
+		patterns = []string{"{name}.yaml", "{name}.yml", "{name}.toml", "{name}.json"}
+	}
+
+	dir, file := path.Split(path.Clean(name))
+	base := strings.TrimSuffix(file, path.Ext(file))
+	r := strings.NewReplacer("{name}", file, "{base}", base)
+
+	names := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		names = append(names, path.Join(dir, r.Replace(pattern)))
+	}
+
+	return names
+}
@@ -0,0 +1,90 @@
+package frontmatter_test
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestSidecar(t *testing.T) {
+	fsys := fstest.MapFS{
+		"photo.jpg":           {Data: []byte("JPEG")},
+		"photo.jpg.yaml":      {Data: []byte("title: Photo\ntags: [photo]\n")},
+		"photo.jpg.json":      {Data: []byte(`{"camera": "X100"}`)},
+		"post.md":             {Data: []byte("---\ntitle: Post\ntags: [go]\n---\nbody")},
+		"post.md.toml":        {Data: []byte("title = \"Sidecar\"\ntags = [\"toml\"]\ndraft = true\n")},
+		"notes.md":            {Data: []byte("notes")},
+		".meta/notes.md.json": {Data: []byte(`{"title": "Notes"}`)},
+		"invalid.png":         {Data: []byte("PNG")},
+		"invalid.png.yaml":    {Data: []byte("title: [")},
+	}
+
+	inputs := []struct {
+		sidecar *frontmatter.Sidecar
+		name    string
+		exp     frontmatter.Matter
+		body    string
+	}{
+		{
+			&frontmatter.Sidecar{FS: fsys},
+			"photo.jpg",
+			frontmatter.Matter{"title": "Photo", "tags": []interface{}{"photo"}, "camera": "X100"},
+			"",
+		},
+		{
+			&frontmatter.Sidecar{FS: fsys},
+			"post.md",
+			frontmatter.Matter{"title": "Post", "tags": []interface{}{"go"}, "draft": true},
+			"body",
+		},
+		{
+			&frontmatter.Sidecar{
+				FS:             fsys,
+				PreferSidecars: true,
+				Merge:          frontmatter.MergeOptions{Lists: frontmatter.ListAppend},
+			},
+			"post.md",
+			frontmatter.Matter{"title": "Sidecar", "tags": []interface{}{"go", "toml"}, "draft": true},
+			"body",
+		},
+		{
+			&frontmatter.Sidecar{FS: fsys, Patterns: []string{".meta/{name}.json"}},
+			"notes.md",
+			frontmatter.Matter{"title": "Notes"},
+			"notes",
+		},
+		{
+			&frontmatter.Sidecar{FS: fsys, Patterns: []string{"{base}.yaml"}},
+			"photo.jpg",
+			frontmatter.Matter{},
+			"",
+		},
+	}
+
+	for _, input := range inputs {
+		m, body, err := input.sidecar.Load(input.name)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", input.name, err)
+		}
+		if !reflect.DeepEqual(m, input.exp) {
+			t.Fatalf("%s: expected %v, got %v", input.name, input.exp, m)
+		}
+		if string(body) != input.body {
+			t.Fatalf("%s: expected body %q, got %q", input.name, input.body, body)
+		}
+	}
+
+	s := &frontmatter.Sidecar{FS: fsys, Patterns: []string{"{name}.yaml", "meta/{base}.json"}}
+	if names := s.Sidecars("img/photo.jpg"); !reflect.DeepEqual(names, []string{"img/photo.jpg.yaml", "img/meta/photo.json"}) {
+		t.Fatalf("unexpected sidecars: %v", names)
+	}
+
+	// Errors.
+	for _, name := range []string{"missing.jpg", "invalid.png"} {
+		if _, _, err := s.Load(name); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package frontmatter
+
+import "bytes"
+
+type lineSpan struct {
+	start int
+	end   int
+	text  []byte
+}
+
+// parseTail searches for a front matter at the end of the input data. The
+// front matter must be the last delimited block of the data, optionally
+// followed by empty lines. Header formats are not supported.
+func (p *parser) parseTail(v interface{}, formats []*Format,
+	mustParse bool) (*Document, error) {
+	// Read all data.
+	if err := p.readAll(); err != nil {
+		return nil, err
+	}
+	data := p.buf
+
+	// Split data into lines and skip the trailing empty lines.
+	lines := splitLines(data)
+	last := len(lines) - 1
+	for last >= 0 && len(lines[last].text) == 0 {
+		last--
+	}
+
+	f, first := detectTail(lines, last, formats)
+	if f == nil {
+		if mustParse {
+			return nil, ErrNotFound
+		}
+		return &Document{Body: data}, nil
+	}
+
+	// Extract front matter.
+	var matter []byte
+	switch {
+	case first == last:
+		delim, _ := f.trim(lines[last].text)
+		matter, _ = f.inline(delim)
+	case f.UnmarshalDelims:
+		matter = data[lines[first].start:lines[last].end]
+	default:
+		matter = data[lines[first].end:lines[last].start]
+	}
+	if err := f.Unmarshal(f.strip(matter), v); err != nil {
+		return nil, err
+	}
+
+	return &Document{
+		Format: f,
+		Matter: data[lines[first].start:lines[last].end],
+		Body:   data[:lines[first].start],
+	}, nil
+}
+
+// detectTail returns the format of the front matter ending at the specified
+// line, along with the index of its first line.
+func detectTail(lines []lineSpan, last int, formats []*Format) (*Format, int) {
+	if last < 0 {
+		return nil, -1
+	}
+
+	// Find the formats ending at the last line.
+	var candidates []*Format
+	for _, f := range formats {
+		if f.isHeader() {
+			continue
+		}
+
+		delim, ok := f.trim(lines[last].text)
+		if !ok {
+			continue
+		}
+		if _, ok := f.inline(delim); ok {
+			return f, last
+		}
+		if string(delim) == f.End {
+			candidates = append(candidates, f)
+		}
+	}
+
+	// Search for the nearest start delimiter.
+	for i := last - 1; i >= 0 && len(candidates) > 0; i-- {
+		n := 0
+		for _, f := range candidates {
+			delim, ok := f.trim(lines[i].text)
+			if !ok {
+				// Prefixed front matters cannot contain unprefixed lines.
+				continue
+			}
+			if string(delim) == f.Start {
+				return f, i
+			}
+
+			candidates[n] = f
+			n++
+		}
+		candidates = candidates[:n]
+	}
+
+	return nil, -1
+}
+
+func splitLines(data []byte) []lineSpan {
+	var lines []lineSpan
+	for start := 0; start < len(data); {
+		end := len(data)
+		if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+			end = start + i + 1
+		}
+
+		lines = append(lines, lineSpan{
+			start: start,
+			end:   end,
+			text:  bytes.TrimSpace(data[start:end]),
+		})
+		start = end
+	}
+
+	return lines
+}
@@ -0,0 +1,308 @@
+// Package taxonomy aggregates the values of front matter fields (e.g.
+// `tags` or `categories`) across many documents, in order to build
+// taxonomy indexes mapping each term to the documents which use it.
+//
+// Terms are grouped by their slug, so `Go`, `go` and ` GO ` are considered
+// the same term. The name of a term is the first value found for it, in the
+// order the documents were added. E.g.:
+//
+//	idx, err := taxonomy.Build(os.DirFS("content"), ".", &taxonomy.Options{
+//		Fields: []taxonomy.Field{
+//			{Name: "tags"},
+//			{Name: "keywords", Taxonomy: "tags"},
+//			{Name: "categories"},
+//		},
+//	})
+//	if err != nil {
+//		// Treat error.
+//	}
+//
+//	for _, term := range idx.Taxonomy("tags").Terms() {
+//		fmt.Println(term.Name, term.Slug, term.Count)
+//	}
+package taxonomy
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/adrg/frontmatter"
+)
+
+// Field describes a front matter field aggregated into a taxonomy.
+type Field struct {
+	// Name is the path of the field in the front matter (e.g. `tags` or
+	// `meta.keywords`). The field can contain a single value or a list.
+	Name string
+
+	// Taxonomy is the name of the taxonomy the values of the field are
+	// added to. Multiple fields can share the same taxonomy.
+	// If empty, the name of the field is used.
+	Taxonomy string
+
+	// Slugify is used to normalize the values of the field. Values with the
+	// same slug are grouped into the same term. If nil, `Slugify` is used.
+	Slugify func(string) string
+}
+
+func (f Field) taxonomy() string {
+	if f.Taxonomy == "" {
+		return f.Name
+	}
+
+	return f.Taxonomy
+}
+
+func (f Field) slugify(s string) string {
+	if f.Slugify == nil {
+		return Slugify(s)
+	}
+
+	return f.Slugify(s)
+}
+
+// DefaultFields contains the fields aggregated if no fields are specified.
+var DefaultFields = []Field{
+	{Name: "tags"},
+	{Name: "categories"},
+}
+
+// Term is a value of a taxonomy, along with the documents using it.
+type Term struct {
+	// Name is the first value found for the term.
+	Name string `json:"name"`
+
+	// Slug is the normalized value of the term.
+	Slug string `json:"slug"`
+
+	// Count is the number of documents using the term.
+	Count int `json:"count"`
+
+	// Documents contains the sorted names of the documents using the term.
+	Documents []string `json:"documents"`
+
+	docs map[string]struct{}
+}
+
+func (t *Term) add(doc string) {
+	if _, ok := t.docs[doc]; ok {
+		return
+	}
+	t.docs[doc] = struct{}{}
+
+	i := sort.SearchStrings(t.Documents, doc)
+	t.Documents = append(t.Documents, "")
+	copy(t.Documents[i+1:], t.Documents[i:])
+	t.Documents[i] = doc
+	t.Count = len(t.Documents)
+}
+
+// Taxonomy holds the terms of a taxonomy.
+type Taxonomy struct {
+	// Name is the name of the taxonomy.
+	Name string
+
+	terms map[string]*Term
+}
+
+// Term returns the term with the specified slug, or nil if the term
+// does not exist.
+func (t *Taxonomy) Term(slug string) *Term {
+	if t == nil {
+		return nil
+	}
+
+	return t.terms[slug]
+}
+
+// Terms returns the terms of the taxonomy, sorted by slug.
+func (t *Taxonomy) Terms() []*Term {
+	if t == nil {
+		return nil
+	}
+
+	terms := make([]*Term, 0, len(t.terms))
+	for _, term := range t.terms {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		return terms[i].Slug < terms[j].Slug
+	})
+
+	return terms
+}
+
+// ByCount returns the terms of the taxonomy, sorted by the number of
+// documents using them, in descending order. Terms used by the same number
+// of documents are sorted by slug.
+func (t *Taxonomy) ByCount() []*Term {
+	terms := t.Terms()
+	sort.SliceStable(terms, func(i, j int) bool {
+		return terms[i].Count > terms[j].Count
+	})
+
+	return terms
+}
+
+// MarshalJSON returns the JSON encoding of the terms of the taxonomy,
+// sorted by slug.
+func (t *Taxonomy) MarshalJSON() ([]byte, error) {
+	terms := t.Terms()
+	if terms == nil {
+		terms = []*Term{}
+	}
+
+	return json.Marshal(terms)
+}
+
+// Index holds the taxonomies built from a set of documents.
+type Index struct {
+	fields     []Field
+	taxonomies map[string]*Taxonomy
+}
+
+// New returns an empty index which aggregates the specified fields.
+// If no fields are provided, the `DefaultFields` are used.
+func New(fields ...Field) *Index {
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+
+	idx := &Index{
+		fields:     fields,
+		taxonomies: map[string]*Taxonomy{},
+	}
+	for _, f := range fields {
+		name := f.taxonomy()
+		if _, ok := idx.taxonomies[name]; !ok {
+			idx.taxonomies[name] = &Taxonomy{Name: name, terms: map[string]*Term{}}
+		}
+	}
+
+	return idx
+}
+
+// Add adds the values of the aggregated fields found in the specified
+// front matter to the index. Values which are not strings, numbers or
+// booleans, and values with an empty slug are ignored.
+func (x *Index) Add(doc string, m frontmatter.Matter) {
+	for _, f := range x.fields {
+		tax := x.taxonomies[f.taxonomy()]
+		for _, val := range m.Strings(f.Name) {
+			val = strings.TrimSpace(val)
+
+			slug := f.slugify(val)
+			if slug == "" {
+				continue
+			}
+
+			term, ok := tax.terms[slug]
+			if !ok {
+				term = &Term{Name: val, Slug: slug, docs: map[string]struct{}{}}
+				tax.terms[slug] = term
+			}
+			term.add(doc)
+		}
+	}
+}
+
+// Taxonomy returns the taxonomy with the specified name, or nil if the
+// taxonomy does not exist.
+func (x *Index) Taxonomy(name string) *Taxonomy {
+	return x.taxonomies[name]
+}
+
+// Taxonomies returns the taxonomies of the index, sorted by name.
+func (x *Index) Taxonomies() []*Taxonomy {
+	taxonomies := make([]*Taxonomy, 0, len(x.taxonomies))
+	for _, tax := range x.taxonomies {
+		taxonomies = append(taxonomies, tax)
+	}
+	sort.Slice(taxonomies, func(i, j int) bool {
+		return taxonomies[i].Name < taxonomies[j].Name
+	})
+
+	return taxonomies
+}
+
+// MarshalJSON returns the JSON encoding of the index, as an object mapping
+// the name of each taxonomy to its terms. E.g.:
+//
+//	{"tags":[{"name":"Go","slug":"go","count":1,"documents":["a.md"]}]}
+func (x *Index) MarshalJSON() ([]byte, error) {
+	return json.Marshal(x.taxonomies)
+}
+
+// WriteJSON writes the indented JSON encoding of the index to w.
+func (x *Index) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(x)
+}
+
+// Options holds the configuration used to build an index.
+type Options struct {
+	// Fields defines the aggregated fields.
+	// If no fields are provided, the `DefaultFields` are used.
+	Fields []Field
+
+	// Pattern defines the pattern matched against the names of the
+	// documents, using the syntax of `path.Match`. If empty, `*.md` is used.
+	Pattern string
+
+	// Parse holds the configuration used to parse the documents.
+	Parse *frontmatter.Options
+}
+
+// Build parses the documents found in the specified directory and its
+// subdirectories and returns the index built from their front matter.
+// The documents are identified by their slash-separated paths.
+func Build(fsys fs.FS, root string, opts *Options) (*Index, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	idx := New(opts.Fields...)
+	err := frontmatter.Walk(fsys, root, opts.Pattern, func(name string, d fs.DirEntry) error {
+		m, _, err := frontmatter.ParseFile(fsys, name, opts.Parse)
+		if err != nil {
+			return err
+		}
+
+		idx.Add(name, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return idx, nil
+}
+
+// Slugify returns the lowercase version of the specified value, with runs
+// of characters other than letters and digits replaced by a single dash.
+// Leading and trailing dashes are removed. E.g.: `Go & YAML` becomes
+// `go-yaml`.
+func Slugify(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s))
+
+	dash := false
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = sb.Len() > 0
+			continue
+		}
+		if dash {
+			sb.WriteByte('-')
+			dash = false
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+
+	return sb.String()
+}
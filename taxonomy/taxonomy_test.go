@@ -0,0 +1,129 @@
+package taxonomy_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adrg/frontmatter"
+	"github.com/adrg/frontmatter/taxonomy"
+)
+
+func TestSlugify(t *testing.T) {
+	testCases := []struct {
+		input  string
+		output string
+	}{
+		{"Go", "go"},
+		{"  Go & YAML  ", "go-yaml"},
+		{"C++", "c"},
+		{"Front Matter 2.0", "front-matter-2-0"},
+		{"Über Straße", "über-straße"},
+		{"--", ""},
+	}
+
+	for _, tc := range testCases {
+		if output := taxonomy.Slugify(tc.input); output != tc.output {
+			t.Fatalf("Input: `%s`\n\nexpected `%s`, got `%s`", tc.input, tc.output, output)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.md":      {Data: []byte("---\ntags: [Go, YAML]\ncategories: Guides\nkeywords: [go]\n---\n")},
+		"b.md":      {Data: []byte("+++\ntags = [\"go\", \"TOML\", \" Go \"]\n+++\n")},
+		"blog/c.md": {Data: []byte("{\n  \"tags\": [\"yaml\", 1], \"categories\": [\"guides\", \"News\"]\n}\n")},
+		"blog/d.md": {Data: []byte("no front matter")},
+		"e.txt":     {Data: []byte("---\ntags: [ignored]\n---\n")},
+	}
+
+	idx, err := taxonomy.Build(fsys, ".", &taxonomy.Options{
+		Fields: []taxonomy.Field{
+			{Name: "tags"},
+			{Name: "keywords", Taxonomy: "tags"},
+			{Name: "categories"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{
+  "categories": [
+    {"name": "Guides", "slug": "guides", "count": 2, "documents": ["a.md", "blog/c.md"]},
+    {"name": "News", "slug": "news", "count": 1, "documents": ["blog/c.md"]}
+  ],
+  "tags": [
+    {"name": "1", "slug": "1", "count": 1, "documents": ["blog/c.md"]},
+    {"name": "Go", "slug": "go", "count": 2, "documents": ["a.md", "b.md"]},
+    {"name": "TOML", "slug": "toml", "count": 1, "documents": ["b.md"]},
+    {"name": "YAML", "slug": "yaml", "count": 2, "documents": ["a.md", "blog/c.md"]}
+  ]
+}`
+
+	var buf bytes.Buffer
+	if err := idx.WriteJSON(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got, want interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+
+	var names []string
+	for _, term := range idx.Taxonomy("tags").ByCount() {
+		names = append(names, term.Slug)
+	}
+	if expected := []string{"go", "yaml", "1", "toml"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+	if term := idx.Taxonomy("tags").Term("go"); term == nil || term.Name != "Go" {
+		t.Fatalf("unexpected term: %v", term)
+	}
+	if idx.Taxonomy("missing").Term("go") != nil || idx.Taxonomy("missing").Terms() != nil {
+		t.Fatal("expected no terms for missing taxonomy")
+	}
+	if tax := idx.Taxonomies(); len(tax) != 2 || tax[0].Name != "categories" {
+		t.Fatalf("unexpected taxonomies: %v", tax)
+	}
+
+	fsys["invalid.md"] = &fstest.MapFile{Data: []byte("---\ntags: [\n---\n")}
+	if _, err := taxonomy.Build(fsys, ".", nil); err == nil || !strings.Contains(err.Error(), "invalid.md") {
+		t.Fatalf("expected error for invalid document, got %v", err)
+	}
+}
+
+func TestIndexAdd(t *testing.T) {
+	idx := taxonomy.New(taxonomy.Field{
+		Name:    "meta.series",
+		Slugify: strings.ToUpper,
+	})
+	idx.Add("b.md", frontmatter.Matter{"meta": map[string]interface{}{"series": "intro"}})
+	idx.Add("a.md", frontmatter.Matter{"meta": map[string]interface{}{"series": "Intro"}})
+	idx.Add("c.md", frontmatter.Matter{"meta": "intro"})
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"meta.series":[{"name":"intro","slug":"INTRO","count":2,"documents":["a.md","b.md"]}]}`
+	if string(data) != expected {
+		t.Fatalf("expected %s, got %s", expected, data)
+	}
+
+	if data, _ := json.Marshal(taxonomy.New()); string(data) != `{"categories":[],"tags":[]}` {
+		t.Fatalf("unexpected empty index: %s", data)
+	}
+}
@@ -0,0 +1,376 @@
+package frontmatter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"time"
+)
+
+// EventType defines the type of a watcher event.
+type EventType int
+
+// Watcher event types.
+const (
+	// EventCreated reports a document which was created.
+	EventCreated EventType = iota + 1
+
+	// EventModified reports a document whose front matter or body changed.
+	EventModified
+
+	// EventRemoved reports a document which was removed.
+	EventRemoved
+)
+
+// String returns the name of the event type.
+func (t EventType) String() string {
+	switch t {
+	case EventCreated:
+		return "created"
+	case EventModified:
+		return "modified"
+	case EventRemoved:
+		return "removed"
+	}
+
+	return "unknown"
+}
+
+// Event describes a change of a watched document.
+type Event struct {
+	// Type is the type of the event.
+	Type EventType
+
+	// Name is the slash-separated path of the document, relative to the
+	// watched directory.
+	Name string
+
+	// Matter is the new front matter of the document.
+	// It is nil for removed documents.
+	Matter Matter
+
+	// Body is the new body of the document.
+	// It is nil for removed documents.
+	Body []byte
+
+	// Changes contains the changes of the front matter fields, relative to
+	// the previous version of the document, as returned by `DiffMatter`.
+	// It is empty if only the body of the document changed.
+	Changes []FieldChange
+
+	// Err reports the error encountered while parsing the document. If
+	// set, the front matter, the body and the changes of the event are
+	// not set.
+	Err error
+}
+
+// WatchOptions holds the configuration used to watch directories.
+type WatchOptions struct {
+	// Pattern defines the pattern matched against the names of the watched
+	// documents, using the syntax of `path.Match`. If empty, `*.md` is used.
+	Pattern string
+
+	// Poll specifies whether the directory is polled for changes, even if
+	// file system notifications are supported. Notifications (inotify) are
+	// only supported on Linux.
+	Poll bool
+
+	// Interval defines the polling interval. If 0, 500ms is used.
+	Interval time.Duration
+
+	// Debounce defines the time a document must remain unchanged before its
+	// changes are reported. It prevents reporting multiple events for a
+	// single write. If 0, 100ms is used.
+	Debounce time.Duration
+
+	// Options holds the configuration used to parse the documents.
+	Options *Options
+}
+
+// Watch watches the specified directory and its subdirectories, and reports
+// the changes of the documents through the returned channel. The documents
+// existing when the watcher starts are parsed, but not reported. Events are
+// only reported if the front matter or the body of a document changed.
+// The watcher stops, and the channel is closed, when the context is done.
+func Watch(ctx context.Context, dir string, opts *WatchOptions) (<-chan Event, error) {
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+
+	w := &watcher{
+		dir:     dir,
+		fsys:    os.DirFS(dir),
+		opts:    *opts,
+		files:   map[string]*watchedFile{},
+		pending: map[string]*pendingFile{},
+		events:  make(chan Event),
+	}
+	if w.opts.Pattern == "" {
+		w.opts.Pattern = "*.md"
+	}
+	if w.opts.Interval <= 0 {
+		w.opts.Interval = 500 * time.Millisecond
+	}
+	if w.opts.Debounce <= 0 {
+		w.opts.Debounce = 100 * time.Millisecond
+	}
+
+	// Start listening for notifications before the initial scan, in order
+	// to avoid missing changes.
+	ctx, cancel := context.WithCancel(ctx)
+	var notify <-chan string
+	if !w.opts.Poll {
+		var err error
+		if notify, err = w.notify(ctx); err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	if err := w.scan(); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go w.run(ctx, cancel, notify)
+	return w.events, nil
+}
+
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	matter  Matter
+	body    []byte
+}
+
+type pendingFile struct {
+	since   time.Time
+	modTime time.Time
+	size    int64
+}
+
+type watcher struct {
+	dir     string
+	fsys    fs.FS
+	opts    WatchOptions
+	files   map[string]*watchedFile
+	pending map[string]*pendingFile
+	events  chan Event
+}
+
+func (w *watcher) run(ctx context.Context, cancel context.CancelFunc, notify <-chan string) {
+	defer close(w.events)
+	defer cancel()
+
+	// Poll the directory if notifications are not used.
+	var poll <-chan time.Time
+	var ticker *time.Ticker
+	startPolling := func() {
+		ticker = time.NewTicker(w.opts.Interval)
+		poll = ticker.C
+	}
+	defer func() {
+		if ticker != nil {
+			ticker.Stop()
+		}
+	}()
+	if notify == nil {
+		startPolling()
+	}
+
+	flush := time.NewTicker(w.opts.Debounce / 2)
+	defer flush.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case name, ok := <-notify:
+			if !ok {
+				// Fall back to polling if notifications stop.
+				notify = nil
+				startPolling()
+				continue
+			}
+			if name == "" {
+				// Rescan the directory if notifications were dropped.
+				w.poll()
+				continue
+			}
+			w.reset(name)
+		case <-poll:
+			w.poll()
+		case <-flush.C:
+			if !w.flush(ctx) {
+				return
+			}
+		}
+	}
+}
+
+// scan parses the documents of the watched directory.
+func (w *watcher) scan() error {
+	return Walk(w.fsys, ".", w.opts.Pattern, func(name string, d fs.DirEntry) error {
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		m, body, err := w.parse(name)
+		if err != nil {
+			// Documents which cannot be parsed are reported once fixed.
+			m, body = nil, nil
+		}
+
+		w.files[name] = &watchedFile{
+			modTime: info.ModTime(),
+			size:    info.Size(),
+			matter:  m,
+			body:    body,
+		}
+		return nil
+	})
+}
+
+// poll marks the documents which changed since they were last processed
+// as pending.
+func (w *watcher) poll() {
+	seen := map[string]bool{}
+	fs.WalkDir(w.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !w.match(name) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		seen[name] = true
+
+		if f := w.files[name]; f == nil || !f.modTime.Equal(info.ModTime()) || f.size != info.Size() {
+			w.touch(name, info.ModTime(), info.Size())
+		}
+		return nil
+	})
+
+	for name := range w.files {
+		if !seen[name] {
+			w.touch(name, time.Time{}, -1)
+		}
+	}
+}
+
+// touch marks the specified document as pending. The debounce period is
+// restarted if the state of the document changed. Documents which do not
+// exist have a negative size.
+func (w *watcher) touch(name string, modTime time.Time, size int64) {
+	p := w.pending[name]
+	if p != nil && p.size == size && p.modTime.Equal(modTime) {
+		return
+	}
+
+	w.pending[name] = &pendingFile{since: time.Now(), modTime: modTime, size: size}
+}
+
+// reset marks the specified document as pending, restarting its debounce
+// period.
+func (w *watcher) reset(name string) {
+	w.pending[name] = &pendingFile{since: time.Now(), size: -1}
+}
+
+// flush processes the pending documents whose debounce period elapsed.
+// It reports whether the watcher should continue running.
+func (w *watcher) flush(ctx context.Context) bool {
+	now := time.Now()
+	for name, p := range w.pending {
+		if now.Sub(p.since) < w.opts.Debounce {
+			continue
+		}
+		delete(w.pending, name)
+
+		event, ok := w.process(name)
+		if !ok {
+			continue
+		}
+
+		select {
+		case w.events <- event:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
+
+// process updates the state of the specified document, and returns the
+// event describing its changes, if any.
+func (w *watcher) process(name string) (Event, bool) {
+	prev := w.files[name]
+
+	info, err := fs.Stat(w.fsys, name)
+	if err != nil || info.IsDir() || !w.match(name) {
+		if prev == nil || (err != nil && !errors.Is(err, fs.ErrNotExist)) {
+			return Event{}, false
+		}
+
+		delete(w.files, name)
+		return Event{
+			Type:    EventRemoved,
+			Name:    name,
+			Changes: DiffMatter(prev.matter, nil),
+		}, true
+	}
+
+	typ := EventCreated
+	if prev != nil {
+		typ = EventModified
+	}
+
+	m, body, err := w.parse(name)
+	if err != nil {
+		if prev == nil {
+			prev = &watchedFile{}
+			w.files[name] = prev
+		}
+		prev.modTime, prev.size = info.ModTime(), info.Size()
+		return Event{Type: typ, Name: name, Err: err}, true
+	}
+
+	f := &watchedFile{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		matter:  m,
+		body:    body,
+	}
+	w.files[name] = f
+
+	var old Matter
+	if prev != nil {
+		old = prev.matter
+	}
+	changes := DiffMatter(old, m)
+	if prev != nil && prev.matter != nil && len(changes) == 0 && bytes.Equal(prev.body, body) {
+		return Event{}, false
+	}
+
+	return Event{
+		Type:    typ,
+		Name:    name,
+		Matter:  m,
+		Body:    body,
+		Changes: changes,
+	}, true
+}
+
+func (w *watcher) parse(name string) (Matter, []byte, error) {
+	return parseFile(w.fsys, w.opts.Options, name)
+}
+
+func (w *watcher) match(name string) bool {
+	ok, _ := path.Match(w.opts.Pattern, path.Base(name))
+	return ok
+}
@@ -0,0 +1,136 @@
+//go:build linux
+
+package frontmatter
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_ATTRIB | syscall.IN_DELETE | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO
+
+// notify watches the directory using inotify, and returns a channel
+// reporting the names of the changed files. An empty name is sent if
+// notifications were dropped, and the directory must be rescanned.
+func (w *watcher) notify(ctx context.Context) (<-chan string, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	// Use a non-blocking file, so that reads are interrupted when the file
+	// is closed.
+	n := &inotify{fd: fd, file: os.NewFile(uintptr(fd), "inotify"), dirs: map[int]string{}}
+	if err := n.addDir(w.dir, "."); err != nil {
+		n.file.Close()
+		return nil, err
+	}
+
+	names := make(chan string, 64)
+	go func() {
+		<-ctx.Done()
+		n.file.Close()
+	}()
+	go func() {
+		defer close(names)
+		n.read(ctx, w.dir, names)
+	}()
+
+	return names, nil
+}
+
+type inotify struct {
+	fd   int
+	file *os.File
+	mu   sync.Mutex
+	dirs map[int]string
+}
+
+// addDir watches the specified directory and its subdirectories. The name
+// of the directory is relative to the watched root.
+func (n *inotify) addDir(root, name string) error {
+	return fs.WalkDir(os.DirFS(root), name, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return err
+		}
+
+		wd, err := syscall.InotifyAddWatch(n.fd, filepath.Join(root, filepath.FromSlash(name)), inotifyMask)
+		if err != nil {
+			return os.NewSyscallError("inotify_add_watch", err)
+		}
+
+		n.mu.Lock()
+		n.dirs[wd] = name
+		n.mu.Unlock()
+		return nil
+	})
+}
+
+func (n *inotify) read(ctx context.Context, root string, names chan<- string) {
+	send := func(name string) bool {
+		select {
+		case names <- name:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+	for {
+		count, err := n.file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= count; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			nameData := buf[offset+syscall.SizeofInotifyEvent : offset+syscall.SizeofInotifyEvent+int(event.Len)]
+			offset += syscall.SizeofInotifyEvent + int(event.Len)
+
+			if event.Mask&syscall.IN_Q_OVERFLOW != 0 {
+				if !send("") {
+					return
+				}
+				continue
+			}
+
+			n.mu.Lock()
+			dir, ok := n.dirs[int(event.Wd)]
+			if event.Mask&syscall.IN_IGNORED != 0 {
+				delete(n.dirs, int(event.Wd))
+			}
+			n.mu.Unlock()
+			if !ok || len(nameData) == 0 {
+				continue
+			}
+
+			name := path.Join(dir, string(bytes.TrimRight(nameData, "\x00")))
+			if event.Mask&syscall.IN_ISDIR != 0 {
+				// Watch new directories, and rescan in order to find the
+				// files created before the watch was added. Removed
+				// directories are handled by rescanning as well.
+				if event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+					// The directory might have been removed already.
+					_ = n.addDir(root, name)
+				}
+				if !send("") {
+					return
+				}
+				continue
+			}
+
+			if !send(name) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package frontmatter
+
+import "context"
+
+// notify returns a nil channel, as file system notifications are not
+// supported, so the watched directory is polled.
+func (w *watcher) notify(ctx context.Context) (<-chan string, error) {
+	return nil, nil
+}
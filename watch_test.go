@@ -0,0 +1,110 @@
+package frontmatter_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/adrg/frontmatter"
+)
+
+func TestWatch(t *testing.T) {
+	for _, poll := range []bool{true, false} {
+		testWatch(t, poll)
+	}
+}
+
+func testWatch(t *testing.T, poll bool) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		t.Helper()
+
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("post.md", "---\ntitle: Post\ntags: [go]\n---\nbody")
+	write("image.png", "PNG")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := frontmatter.Watch(ctx, dir, &frontmatter.WatchOptions{
+		Poll:     poll,
+		Interval: 20 * time.Millisecond,
+		Debounce: 40 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := func() frontmatter.Event {
+		t.Helper()
+
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatalf("poll %t: timed out waiting for event", poll)
+		}
+		return frontmatter.Event{}
+	}
+
+	// Modified front matter.
+	write("post.md", "---\ntitle: Post\ntags: [go]\n---\nbody")
+	write("post.md", "---\ntitle: New post\nauthor: John Doe\n---\nbody")
+	event := next()
+	expChanges := []frontmatter.FieldChange{
+		{Type: frontmatter.FieldAdded, Path: "author", New: "John Doe"},
+		{Type: frontmatter.FieldRemoved, Path: "tags", Old: []interface{}{"go"}},
+		{Type: frontmatter.FieldModified, Path: "title", Old: "Post", New: "New post"},
+	}
+	if event.Type != frontmatter.EventModified || event.Name != "post.md" ||
+		event.Matter.String("title") != "New post" || string(event.Body) != "body" {
+		t.Fatalf("poll %t: unexpected event: %+v", poll, event)
+	}
+	if !reflect.DeepEqual(event.Changes, expChanges) {
+		t.Fatalf("poll %t: expected changes %+v, got %+v", poll, expChanges, event.Changes)
+	}
+
+	// Modified body.
+	write("post.md", "---\nauthor: John Doe\ntitle: New post\n---\nnew body")
+	if event = next(); event.Type != frontmatter.EventModified || len(event.Changes) != 0 ||
+		string(event.Body) != "new body" {
+		t.Fatalf("poll %t: unexpected event: %+v", poll, event)
+	}
+
+	// Created documents.
+	write("blog/first.md", "---\ntitle: First\n---\n")
+	if event = next(); event.Type != frontmatter.EventCreated || event.Name != "blog/first.md" ||
+		event.Matter.String("title") != "First" {
+		t.Fatalf("poll %t: unexpected event: %+v", poll, event)
+	}
+
+	// Invalid documents.
+	write("blog/first.md", "---\ntitle: [\n---\n")
+	if event = next(); event.Type != frontmatter.EventModified || event.Err == nil {
+		t.Fatalf("poll %t: unexpected event: %+v", poll, event)
+	}
+
+	// Removed documents.
+	if err := os.Remove(filepath.Join(dir, "post.md")); err != nil {
+		t.Fatal(err)
+	}
+	if event = next(); event.Type != frontmatter.EventRemoved || event.Name != "post.md" ||
+		event.Matter != nil || len(event.Changes) != 2 {
+		t.Fatalf("poll %t: unexpected event: %+v", poll, event)
+	}
+
+	// The channel is closed when the context is done.
+	cancel()
+	for range events {
+	}
+}